@@ -0,0 +1,106 @@
+// Copyright 2026
+// license that can be found in the LICENSE file.
+
+package netpacket
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AnomalyCode
+// type of protocol violation found during parsing
+type AnomalyCode uint8
+
+const (
+	// AnomalyBadVersion
+	// version field does not match protocol version
+	AnomalyBadVersion AnomalyCode = iota + 1
+	// AnomalyReservedBits
+	// reserved bits which must be zero are set
+	AnomalyReservedBits
+	// AnomalyInvalidLength
+	// length field is smaller than header length
+	AnomalyInvalidLength
+	// AnomalyLengthExceedsData
+	// length field is greater than length of parsed data
+	AnomalyLengthExceedsData
+	// AnomalyTrailingData
+	// data contains bytes beyond length field
+	AnomalyTrailingData
+	// AnomalyTruncatedOptions
+	// header length exceeds length of parsed data, options are not parsed
+	AnomalyTruncatedOptions
+)
+
+var anomalyCodesNames = map[AnomalyCode]string{
+	AnomalyBadVersion:        "bad version",
+	AnomalyReservedBits:      "reserved bits",
+	AnomalyInvalidLength:     "invalid length",
+	AnomalyLengthExceedsData: "length exceeds data",
+	AnomalyTrailingData:      "trailing data",
+	AnomalyTruncatedOptions:  "truncated options",
+}
+
+func (c AnomalyCode) String() string {
+	if name, ok := anomalyCodesNames[c]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("unknown(%d)", uint8(c))
+}
+
+// Anomaly
+// protocol violation found during parsing
+type Anomaly struct {
+	Code AnomalyCode
+	// Layer
+	// kind of layer which contains violation
+	Layer Kind
+	// Offset
+	// byte offset of violating field or data from layer start
+	Offset int
+	// Message
+	// human-readable description of violation
+	Message string
+}
+
+func (a Anomaly) String() string {
+	return fmt.Sprintf("%s %s at offset %d: %s", a.Layer, a.Code, a.Offset, a.Message)
+}
+
+// Anomalies
+// list of protocol violations found during parsing in order of detection
+type Anomalies []Anomaly
+
+// Add
+// appends anomaly to list. Does nothing for nil list pointer
+func (a *Anomalies) Add(anomaly Anomaly) {
+	if a == nil {
+		return
+	}
+
+	*a = append(*a, anomaly)
+}
+
+// Has
+// returns true if list contains anomaly with code
+func (a Anomalies) Has(code AnomalyCode) bool {
+	for _, anomaly := range a {
+		if anomaly.Code == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (a Anomalies) String() string {
+	lines := make([]string, 0, len(a))
+
+	for _, anomaly := range a {
+		lines = append(lines, anomaly.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,74 @@
+// Copyright 2026
+// license that can be found in the LICENSE file.
+
+package checksum
+
+import "encoding/binary"
+
+// Sum
+// adds data to initial one's complement partial sum as 16-bit big endian words
+// If data has odd length last byte padded with zero
+// For summing data by parts all parts except last should have even length
+func Sum(data []byte, initial uint32) uint32 {
+	sum := uint64(initial)
+
+	for len(data) >= 2 {
+		sum += uint64(binary.BigEndian.Uint16(data))
+		data = data[2:]
+	}
+
+	if len(data) == 1 {
+		sum += uint64(data[0]) << 8
+	}
+
+	for sum>>32 != 0 {
+		sum = (sum & 0xFFFFFFFF) + (sum >> 32)
+	}
+
+	return uint32(sum)
+}
+
+// Fold
+// folds partial sum to 16 bits and returns one's complement of it
+func Fold(sum uint32) uint16 {
+	for sum>>16 != 0 {
+		sum = (sum & 0xFFFF) + (sum >> 16)
+	}
+
+	return ^uint16(sum)
+}
+
+// Checksum
+// returns Internet checksum (RFC 1071) of data
+func Checksum(data []byte) uint16 {
+	return Fold(Sum(data, 0))
+}
+
+type Status uint8
+
+const (
+	// StatusNotVerified
+	// checksum was not verified yet
+	StatusNotVerified Status = iota
+	StatusValid
+	StatusInvalid
+	// StatusNotComputed
+	// sender did not compute checksum (zero UDP checksum)
+	StatusNotComputed
+)
+
+var statusesMap = map[Status]string{
+	StatusNotVerified: "not verified",
+	StatusValid:       "valid",
+	StatusInvalid:     "invalid",
+	StatusNotComputed: "not computed",
+}
+
+func (s Status) String() string {
+	str, ok := statusesMap[s]
+	if ok {
+		return str
+	}
+
+	return "unknown"
+}
@@ -0,0 +1,63 @@
+// Copyright 2026
+// license that can be found in the LICENSE file.
+
+package checksum
+
+import (
+	"encoding/binary"
+	"net"
+	"slices"
+)
+
+// IPv4PseudoHeader
+// represents IPv4 pseudo-header used for UDP and TCP checksum calculation
+type IPv4PseudoHeader struct {
+	Source      net.IP
+	Destination net.IP
+	Protocol    uint8
+}
+
+// Sum
+// returns partial sum of pseudo-header for transport segment with length
+func (h *IPv4PseudoHeader) Sum(length int) uint32 {
+	var header [12]byte
+
+	copy(header[0:4], h.Source.To4())
+	copy(header[4:8], h.Destination.To4())
+	header[9] = h.Protocol
+	binary.BigEndian.PutUint16(header[10:12], uint16(length))
+
+	return Sum(header[:], 0)
+}
+
+// Checksum
+// returns Internet checksum over pseudo-header and all segment parts
+// Segment length for pseudo-header is sum of parts lengths
+// All parts except last should have even length
+func (h *IPv4PseudoHeader) Checksum(segment ...[]byte) uint16 {
+	length := 0
+	for _, part := range segment {
+		length += len(part)
+	}
+
+	sum := h.Sum(length)
+	for _, part := range segment {
+		sum = Sum(part, sum)
+	}
+
+	return Fold(sum)
+}
+
+// Clone
+// returns deep copy of pseudo-header
+func (h *IPv4PseudoHeader) Clone() *IPv4PseudoHeader {
+	if h == nil {
+		return nil
+	}
+
+	return &IPv4PseudoHeader{
+		Source:      slices.Clone(h.Source),
+		Destination: slices.Clone(h.Destination),
+		Protocol:    h.Protocol,
+	}
+}
@@ -13,6 +13,8 @@ var (
 	ErrNotImplemented    = errors.New("not implemented yet")
 	ErrShortData         = errors.New("data too short")
 	ErrCannotParseHeader = errors.New("cannot parse header")
+	ErrBadChecksum       = errors.New("bad checksum")
+	ErrCannotSerialize   = errors.New("cannot serialize")
 )
 
 func WrapShortDataErr(err error) error {
@@ -26,3 +28,83 @@ func WrapCannotParseHeaderErr(err error) error {
 func WrapNotImplementedErr(err error) error {
 	return fmt.Errorf("%w: %w", ErrNotImplemented, err)
 }
+
+func WrapBadChecksumErr(err error) error {
+	return fmt.Errorf("%w: %w", ErrBadChecksum, err)
+}
+
+func WrapCannotSerializeErr(err error) error {
+	return fmt.Errorf("%w: %w", ErrCannotSerialize, err)
+}
+
+// ParseError
+// describes field which prevents parsing
+// errors.Is matches ErrShortData if data is shorter than field requires
+// and ErrCannotParseHeader if field contains invalid value
+type ParseError struct {
+	// Kind
+	// kind of layer which cannot be parsed
+	Kind Kind
+	// Field
+	// name of invalid field, for example "total length"
+	Field string
+	// Offset
+	// byte offset of field from layer start
+	Offset int
+	// Expected
+	// constraint for field value, for example ">= 20"
+	Expected string
+	// Actual
+	// actual field value
+	Actual int
+
+	cause error
+}
+
+// NewShortDataErr
+// returns ParseError which matches ErrShortData
+func NewShortDataErr(kind Kind, field string, offset int, expected string, actual int) *ParseError {
+	return &ParseError{
+		Kind:     kind,
+		Field:    field,
+		Offset:   offset,
+		Expected: expected,
+		Actual:   actual,
+		cause:    ErrShortData,
+	}
+}
+
+// NewInvalidFieldErr
+// returns ParseError which matches ErrCannotParseHeader
+func NewInvalidFieldErr(kind Kind, field string, offset int, expected string, actual int) *ParseError {
+	return &ParseError{
+		Kind:     kind,
+		Field:    field,
+		Offset:   offset,
+		Expected: expected,
+		Actual:   actual,
+		cause:    ErrCannotParseHeader,
+	}
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s: %s at offset %d: %s", e.cause, e.Kind, e.Offset, e.mismatch())
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.cause
+}
+
+func (e *ParseError) mismatch() string {
+	return fmt.Sprintf("%s expected %s, actual %d", e.Field, e.Expected, e.Actual)
+}
+
+// WrapParseErr
+// wraps err into ErrCannotParseHeader error if err does not match it
+func WrapParseErr(err error) error {
+	if errors.Is(err, ErrCannotParseHeader) {
+		return err
+	}
+
+	return WrapCannotParseHeaderErr(err)
+}
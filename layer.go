@@ -0,0 +1,130 @@
+// Copyright 2026
+// license that can be found in the LICENSE file.
+
+package netpacket
+
+import (
+	"fmt"
+	"strings"
+
+	stringsutils "github.com/name212/netpacket/utils/strings"
+)
+
+// KindPayload
+// kind of application data which is not decoded by this package
+const KindPayload Kind = "Payload"
+
+// Layer
+// decoded protocol layer
+type Layer interface {
+	Kinder
+
+	// Contents
+	// returns bytes of layer header
+	Contents() []byte
+	// Payload
+	// returns bytes carried by layer
+	Payload() []byte
+	// NextLayerKind
+	// returns kind of layer carried in payload
+	// KindPayload returns if payload cannot be decoded and empty kind if there is no payload
+	NextLayerKind() Kind
+}
+
+func (p Payload) Kind() Kind {
+	return KindPayload
+}
+
+// Contents
+// returns payload bytes
+func (p Payload) Contents() []byte {
+	return p
+}
+
+// Payload
+// returns nil because application data does not carry another layer
+func (p Payload) Payload() []byte {
+	return nil
+}
+
+func (p Payload) NextLayerKind() Kind {
+	return ""
+}
+
+// DecodedPacket
+// ordered layers of decoded packet from outermost to innermost
+type DecodedPacket struct {
+	layers []Layer
+}
+
+// NewDecodedPacket
+// creates decoded packet from layers in order from outermost to innermost
+func NewDecodedPacket(layers ...Layer) *DecodedPacket {
+	return &DecodedPacket{
+		layers: layers,
+	}
+}
+
+// AddLayer
+// appends innermost layer
+func (p *DecodedPacket) AddLayer(layer Layer) {
+	p.layers = append(p.layers, layer)
+}
+
+func (p *DecodedPacket) Layers() []Layer {
+	return p.layers
+}
+
+// Layer
+// returns first layer with kind or nil if packet does not contain it
+func (p *DecodedPacket) Layer(kind Kind) Layer {
+	for _, layer := range p.layers {
+		if layer.Kind() == kind {
+			return layer
+		}
+	}
+
+	return nil
+}
+
+// ApplicationPayload
+// returns innermost layer payload if it is not decoded
+// returns nil if packet does not contain application payload
+func (p *DecodedPacket) ApplicationPayload() []byte {
+	if payload, ok := LayerOf[Payload](p); ok {
+		return payload
+	}
+
+	return nil
+}
+
+func (p *DecodedPacket) String() string {
+	lines := make([]string, 0, len(p.layers)+1)
+	lines = append(lines, "Decoded packet:")
+
+	for _, layer := range p.layers {
+		layerStr := fmt.Sprintf("%s len: %d", layer.Kind(), len(layer.Contents()))
+		if stringer, ok := layer.(fmt.Stringer); ok {
+			layerStr = stringer.String()
+		}
+
+		lines = append(lines, stringsutils.ShiftOnTabs(layerStr, 1))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// LayerOf
+// returns first layer of type T
+// Second value is false if packet does not contain layer of type T
+func LayerOf[T Layer](p *DecodedPacket) (T, bool) {
+	for _, layer := range p.layers {
+		if res, ok := layer.(T); ok {
+			return res, true
+		}
+	}
+
+	var empty T
+
+	return empty, false
+}
@@ -0,0 +1,138 @@
+// Copyright 2026
+// license that can be found in the LICENSE file.
+
+package v4
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+
+	"github.com/name212/netpacket"
+)
+
+var ErrNotFirstFragment = errors.New("not first fragment")
+
+// transportPortsLength
+// source and destination ports are first 4 bytes of TCP and UDP headers
+const transportPortsLength = 4
+
+// GetSourceAddr
+// returns source address as netip.Addr without heap allocations
+// returns zero Addr if source IP is not set
+func (h *Header) GetSourceAddr() netip.Addr {
+	return addrFromIP(h.SourceIP)
+}
+
+// GetDestinationAddr
+// returns destination address as netip.Addr without heap allocations
+// returns zero Addr if destination IP is not set
+func (h *Header) GetDestinationAddr() netip.Addr {
+	return addrFromIP(h.DestinationIP)
+}
+
+// SetSourceAddr
+// sets source IP from IPv4 or IPv4-mapped IPv6 address
+func (h *Header) SetSourceAddr(addr netip.Addr) error {
+	ip, err := ipFromAddr(addr)
+	if err != nil {
+		return err
+	}
+
+	h.SourceIP = ip
+
+	return nil
+}
+
+// SetDestinationAddr
+// sets destination IP from IPv4 or IPv4-mapped IPv6 address
+func (h *Header) SetDestinationAddr(addr netip.Addr) error {
+	ip, err := ipFromAddr(addr)
+	if err != nil {
+		return err
+	}
+
+	h.DestinationIP = ip
+
+	return nil
+}
+
+func (p *Packet) GetSourceAddr() netip.Addr {
+	return p.GetHeader().GetSourceAddr()
+}
+
+func (p *Packet) GetDestinationAddr() netip.Addr {
+	return p.GetHeader().GetDestinationAddr()
+}
+
+// AddrPorts
+// returns source and destination addresses with ports of TCP or UDP packet
+// Ports are read from payload without parsing transport packet and heap allocations
+// returns ErrNotTransportPacket error if packet is not UDP or TCP
+// returns ErrNotFirstFragment error if packet is not first fragment,
+// because its payload does not contain transport header
+func (p *Packet) AddrPorts() (netip.AddrPort, netip.AddrPort, error) {
+	header := p.GetHeader()
+
+	if !p.IsTransport() {
+		return netip.AddrPort{}, netip.AddrPort{}, fmt.Errorf("%w %s", ErrNotTransportPacket, header.ProtocolString())
+	}
+
+	if header.FragmentOffset != 0 {
+		return netip.AddrPort{}, netip.AddrPort{}, fmt.Errorf(
+			"%w: %s fragment with offset %d does not contain ports",
+			ErrNotFirstFragment,
+			header.ProtocolString(),
+			header.FragmentOffset,
+		)
+	}
+
+	payload := p.GetPayload()
+	if len(payload) < transportPortsLength {
+		return netip.AddrPort{}, netip.AddrPort{}, netpacket.NewShortDataErr(
+			Kind,
+			fmt.Sprintf("%s ports", header.ProtocolString()),
+			header.HeaderLen(),
+			fmt.Sprintf(">= %d", transportPortsLength),
+			len(payload),
+		)
+	}
+
+	source := netip.AddrPortFrom(p.GetSourceAddr(), binary.BigEndian.Uint16(payload[0:2]))
+	destination := netip.AddrPortFrom(p.GetDestinationAddr(), binary.BigEndian.Uint16(payload[2:4]))
+
+	return source, destination, nil
+}
+
+// AddrPortsWith
+// combines packet addresses with ports of already parsed transport packet
+// returns source and destination addresses with ports
+func (p *Packet) AddrPortsWith(t Transport) (netip.AddrPort, netip.AddrPort) {
+	source := netip.AddrPortFrom(p.GetSourceAddr(), uint16(t.GetSourcePort()))
+	destination := netip.AddrPortFrom(p.GetDestinationAddr(), uint16(t.GetDestinationPort()))
+
+	return source, destination
+}
+
+func addrFromIP(ip net.IP) netip.Addr {
+	if ip4 := ip.To4(); ip4 != nil {
+		return netip.AddrFrom4([4]byte(ip4))
+	}
+
+	addr, _ := netip.AddrFromSlice(ip)
+
+	return addr
+}
+
+func ipFromAddr(addr netip.Addr) (net.IP, error) {
+	addr = addr.Unmap()
+	if !addr.Is4() {
+		return nil, fmt.Errorf("address %s is not IPv4 address", addr)
+	}
+
+	ip4 := addr.As4()
+
+	return net.IP(ip4[:]), nil
+}
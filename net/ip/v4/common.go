@@ -10,14 +10,17 @@ import (
 )
 
 const (
-	minHeaderLength = 20
+	minHeaderLength  = 20
+	maxHeaderLength  = 60
+	maxOptionsLength = maxHeaderLength - minHeaderLength
+	maxTotalLength   = 0xFFFF
 
 	Kind netpacket.Kind = "IPv4"
 )
 
 func isValidPacket(data []byte) error {
 	if len(data) < minHeaderLength {
-		return netpacket.WrapShortDataErr(fmt.Errorf("IPv4 packet"))
+		return netpacket.NewShortDataErr(Kind, "data length", 0, fmt.Sprintf(">= %d", minHeaderLength), len(data))
 	}
 
 	return nil
@@ -0,0 +1,49 @@
+// Copyright 2026
+// license that can be found in the LICENSE file.
+
+package v4
+
+import "github.com/name212/netpacket"
+
+// Decode
+// parses IPv4 packet and all inner layers which can be decoded:
+// IPv4 packet, UDP datagram or TCP packet and application payload as netpacket.Payload
+// Payload of fragment is returned as netpacket.Payload without decoding transport layer
+// Returns nil packet and error if IPv4 packet or transport layer cannot be parsed,
+// decoded packet is never returned with error. Use ParsePacketWithOptions
+// to get IPv4 packet with not parsed transport payload
+// Options are applied to all layers, data is copied once with opts.Copy
+// With opts.VerifyChecksum transport checksum is verified with IPv4 pseudo-header,
+// status is saved in transport layer and transport with invalid checksum is not rejected
+func Decode(data []byte, opts netpacket.ParseOptions) (*netpacket.DecodedPacket, error) {
+	packet, err := ParsePacketWithOptions(data, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	res := netpacket.NewDecodedPacket(packet)
+
+	switch packet.NextLayerKind() {
+	case "":
+		return res, nil
+	case netpacket.KindPayload:
+		res.AddLayer(netpacket.Payload(packet.GetPayload()))
+		return res, nil
+	}
+
+	// packet payload does not alias data if data was copied
+	opts.Copy = false
+
+	transport, err := packet.transportPacket(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	res.AddLayer(transport)
+
+	if transport.NextLayerKind() == netpacket.KindPayload {
+		res.AddLayer(netpacket.Payload(transport.GetPayload()))
+	}
+
+	return res, nil
+}
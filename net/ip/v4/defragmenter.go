@@ -0,0 +1,441 @@
+// Copyright 2026
+// license that can be found in the LICENSE file.
+
+package v4
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"sync"
+	"time"
+)
+
+var (
+	ErrInvalidFragment        = errors.New("invalid fragment")
+	ErrFragmentsLimitExceeded = errors.New("fragments limit exceeded")
+	ErrMemoryLimitExceeded    = errors.New("defragmenter memory limit exceeded")
+)
+
+const (
+	DefaultDefragmenterTimeout   = 30 * time.Second
+	DefaultMaxFragmentsPerPacket = 64
+	DefaultDefragmenterMaxMemory = 4 * 1024 * 1024
+
+	// maxFragmentsPerPacket
+	// each fragment except last contains at least 8 bytes of payload
+	maxFragmentsPerPacket = maxTotalLength / 8
+	fragmentOffsetUnit    = 8
+)
+
+// OverlapPolicy
+// selects which data is used when fragments overlap
+// Different OS resolve overlaps differently, so IDS should
+// reassemble packets the same way as protected host
+type OverlapPolicy uint8
+
+const (
+	// OverlapPolicyFirst
+	// data from earlier received fragment always wins
+	OverlapPolicyFirst OverlapPolicy = iota
+	// OverlapPolicyLast
+	// data from later received fragment always wins
+	OverlapPolicyLast
+	// OverlapPolicyBSD
+	// later received fragment wins only if it starts before earlier fragment
+	OverlapPolicyBSD
+	// OverlapPolicyLinux
+	// later received fragment wins if it starts before or at the same offset as earlier fragment
+	OverlapPolicyLinux
+)
+
+// DefragmenterConfig
+// zero values replaced with defaults
+type DefragmenterConfig struct {
+	// Timeout
+	// time for receiving all fragments from first received fragment
+	Timeout time.Duration
+	// MaxFragmentsPerPacket
+	// max count of fragments stored for one packet
+	MaxFragmentsPerPacket int
+	// MaxMemory
+	// max bytes of fragments payload stored for all incomplete packets
+	// When limit is reached expired packets are dropped first and then
+	// the oldest incomplete packets
+	MaxMemory int
+	// OverlapPolicy
+	// policy for resolving overlapped fragments
+	OverlapPolicy OverlapPolicy
+}
+
+type fragmentsKey struct {
+	source         [4]byte
+	destination    [4]byte
+	protocol       uint8
+	identification uint16
+}
+
+type fragment struct {
+	offset int
+	data   []byte
+}
+
+func (f *fragment) end() int {
+	return f.offset + len(f.data)
+}
+
+type fragmentsList struct {
+	fragments []fragment
+	// firstHeader
+	// copy of header from fragment with zero offset
+	firstHeader *Header
+	// payloadLen
+	// full payload length. -1 if last fragment was not received
+	payloadLen int
+	size       int
+	deadline   time.Time
+}
+
+// Defragmenter
+// reassembles IPv4 packets from fragments
+// Fragments are grouped by source, destination, protocol and identification
+// Defragmenter is safe for concurrent use
+type Defragmenter struct {
+	mu sync.Mutex
+
+	config  DefragmenterConfig
+	packets map[fragmentsKey]*fragmentsList
+	memory  int
+}
+
+func NewDefragmenter(config DefragmenterConfig) *Defragmenter {
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultDefragmenterTimeout
+	}
+
+	if config.MaxFragmentsPerPacket <= 0 {
+		config.MaxFragmentsPerPacket = DefaultMaxFragmentsPerPacket
+	}
+
+	config.MaxFragmentsPerPacket = min(config.MaxFragmentsPerPacket, maxFragmentsPerPacket)
+
+	if config.MaxMemory <= 0 {
+		config.MaxMemory = DefaultDefragmenterMaxMemory
+	}
+
+	return &Defragmenter{
+		config:  config,
+		packets: make(map[fragmentsKey]*fragmentsList),
+	}
+}
+
+// Defragment
+// same as DefragmentAt with current time
+func (d *Defragmenter) Defragment(packet *Packet) (*Packet, error) {
+	return d.DefragmentAt(packet, time.Now())
+}
+
+// DefragmentAt
+// adds fragment received at now
+// returns packet as is if packet is not fragment
+// returns nil packet and nil error if more fragments are needed
+// returns reassembled packet when all fragments are received
+// Fragments payload is copied, so packet data can be reused after call
+// On invalid fragment or exceeded fragments limit all fragments of packet are dropped
+// On exceeded memory limit fragments of the oldest other incomplete packets are dropped
+func (d *Defragmenter) DefragmentAt(packet *Packet, now time.Time) (*Packet, error) {
+	header := packet.GetHeader()
+
+	if !isFragment(header) {
+		return packet, nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key := newFragmentsKey(header)
+
+	list, ok := d.packets[key]
+	if ok && now.After(list.deadline) {
+		d.drop(key, list)
+		ok = false
+	}
+
+	if !ok {
+		list = &fragmentsList{
+			payloadLen: -1,
+			deadline:   now.Add(d.config.Timeout),
+		}
+	}
+
+	frag, err := newFragment(packet)
+	if err != nil {
+		d.drop(key, list)
+		return nil, err
+	}
+
+	if len(list.fragments) >= d.config.MaxFragmentsPerPacket {
+		d.drop(key, list)
+		return nil, fmt.Errorf(
+			"%w: more than %d fragments for packet %s",
+			ErrFragmentsLimitExceeded,
+			d.config.MaxFragmentsPerPacket,
+			key,
+		)
+	}
+
+	if d.memory+len(frag.data) > d.config.MaxMemory {
+		d.discardExpired(now)
+
+		for d.memory+len(frag.data) > d.config.MaxMemory {
+			if !d.dropOldest(key) {
+				return nil, fmt.Errorf("%w: %d bytes stored", ErrMemoryLimitExceeded, d.memory)
+			}
+		}
+	}
+
+	if err := list.add(frag, header); err != nil {
+		d.drop(key, list)
+		return nil, fmt.Errorf("%w: packet %s: %w", ErrInvalidFragment, key, err)
+	}
+
+	d.packets[key] = list
+	d.memory += len(frag.data)
+
+	if !list.isComplete() {
+		return nil, nil
+	}
+
+	d.drop(key, list)
+
+	return list.reassemble(d.config.OverlapPolicy)
+}
+
+// DiscardExpired
+// drops incomplete packets which were not reassembled before now
+// returns count of dropped packets
+func (d *Defragmenter) DiscardExpired(now time.Time) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.discardExpired(now)
+}
+
+// PendingPackets
+// returns count of incomplete packets
+func (d *Defragmenter) PendingPackets() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return len(d.packets)
+}
+
+func (d *Defragmenter) discardExpired(now time.Time) int {
+	count := 0
+
+	for key, list := range d.packets {
+		if now.After(list.deadline) {
+			d.drop(key, list)
+			count++
+		}
+	}
+
+	return count
+}
+
+// dropOldest
+// drops incomplete packet with the earliest deadline except packet with exceptKey
+// returns false if there is no packet to drop
+func (d *Defragmenter) dropOldest(exceptKey fragmentsKey) bool {
+	var (
+		oldestKey  fragmentsKey
+		oldestList *fragmentsList
+	)
+
+	for key, list := range d.packets {
+		if key == exceptKey {
+			continue
+		}
+
+		if oldestList == nil || list.deadline.Before(oldestList.deadline) {
+			oldestKey = key
+			oldestList = list
+		}
+	}
+
+	if oldestList == nil {
+		return false
+	}
+
+	d.drop(oldestKey, oldestList)
+
+	return true
+}
+
+func (d *Defragmenter) drop(key fragmentsKey, list *fragmentsList) {
+	if _, ok := d.packets[key]; !ok {
+		return
+	}
+
+	d.memory -= list.size
+	delete(d.packets, key)
+}
+
+func isFragment(header *Header) bool {
+	return header.MoreFragments() || header.FragmentOffset != 0
+}
+
+func newFragmentsKey(header *Header) fragmentsKey {
+	key := fragmentsKey{
+		protocol:       header.Protocol,
+		identification: header.Identification,
+	}
+
+	copy(key.source[:], header.SourceIP.To4())
+	copy(key.destination[:], header.DestinationIP.To4())
+
+	return key
+}
+
+func (k fragmentsKey) String() string {
+	return fmt.Sprintf(
+		"%s->%s proto %d id %d",
+		net.IP(k.source[:]).String(),
+		net.IP(k.destination[:]).String(),
+		k.protocol,
+		k.identification,
+	)
+}
+
+func newFragment(packet *Packet) (fragment, error) {
+	header := packet.GetHeader()
+	payload := packet.GetPayload()
+
+	payloadLen := header.GetTotalLen() - header.HeaderLen()
+	if payloadLen >= 0 && payloadLen < len(payload) {
+		payload = payload[:payloadLen]
+	}
+
+	frag := fragment{
+		offset: int(header.FragmentOffset) * fragmentOffsetUnit,
+		data:   slices.Clone(payload),
+	}
+
+	if header.MoreFragments() && (len(frag.data) == 0 || len(frag.data)%fragmentOffsetUnit != 0) {
+		return fragment{}, fmt.Errorf(
+			"%w: fragment payload length %d is not multiple of %d",
+			ErrInvalidFragment,
+			len(frag.data),
+			fragmentOffsetUnit,
+		)
+	}
+
+	if frag.end()+header.HeaderLen() > maxTotalLength {
+		return fragment{}, fmt.Errorf(
+			"%w: fragment end %d exceeds max packet length",
+			ErrInvalidFragment,
+			frag.end(),
+		)
+	}
+
+	return frag, nil
+}
+
+func (l *fragmentsList) add(frag fragment, header *Header) error {
+	if !header.MoreFragments() {
+		if l.payloadLen >= 0 && l.payloadLen != frag.end() {
+			return fmt.Errorf("last fragments with different packet length %d and %d", l.payloadLen, frag.end())
+		}
+
+		l.payloadLen = frag.end()
+	}
+
+	if l.payloadLen >= 0 {
+		for i := range l.fragments {
+			if l.fragments[i].end() > l.payloadLen {
+				return fmt.Errorf("fragment end %d exceeds packet length %d", l.fragments[i].end(), l.payloadLen)
+			}
+		}
+
+		if frag.end() > l.payloadLen {
+			return fmt.Errorf("fragment end %d exceeds packet length %d", frag.end(), l.payloadLen)
+		}
+	}
+
+	if frag.offset == 0 && l.firstHeader == nil {
+		l.firstHeader = header.Clone()
+	}
+
+	l.fragments = append(l.fragments, frag)
+	l.size += len(frag.data)
+
+	return nil
+}
+
+func (l *fragmentsList) isComplete() bool {
+	if l.payloadLen < 0 || l.firstHeader == nil {
+		return false
+	}
+
+	sorted := slices.Clone(l.fragments)
+	slices.SortFunc(sorted, func(a, b fragment) int {
+		return a.offset - b.offset
+	})
+
+	covered := 0
+	for _, frag := range sorted {
+		if frag.offset > covered {
+			return false
+		}
+
+		covered = max(covered, frag.end())
+	}
+
+	return covered >= l.payloadLen
+}
+
+func (l *fragmentsList) reassemble(policy OverlapPolicy) (*Packet, error) {
+	payload := make([]byte, l.payloadLen)
+	// owners
+	// index+1 of fragment which data is written in byte
+	owners := make([]uint16, l.payloadLen)
+
+	for i := range l.fragments {
+		frag := &l.fragments[i]
+
+		for pos := frag.offset; pos < frag.end(); pos++ {
+			owner := owners[pos]
+			if owner != 0 && !newFragmentWins(policy, frag, &l.fragments[owner-1]) {
+				continue
+			}
+
+			payload[pos] = frag.data[pos-frag.offset]
+			owners[pos] = uint16(i + 1)
+		}
+	}
+
+	header := l.firstHeader
+	header.SetMoreFragments(false)
+	header.FragmentOffset = 0
+
+	packet, err := newSerializedPacket(header, payload)
+	if err != nil {
+		return nil, fmt.Errorf("%w: reassembled packet: %w", ErrInvalidFragment, err)
+	}
+
+	return packet, nil
+}
+
+func newFragmentWins(policy OverlapPolicy, newFragment, oldFragment *fragment) bool {
+	switch policy {
+	case OverlapPolicyLast:
+		return true
+	case OverlapPolicyBSD:
+		return newFragment.offset < oldFragment.offset
+	case OverlapPolicyLinux:
+		return newFragment.offset <= oldFragment.offset
+	default:
+		return false
+	}
+}
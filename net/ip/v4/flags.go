@@ -0,0 +1,72 @@
+// Copyright 2026
+// license that can be found in the LICENSE file.
+
+package v4
+
+import "strings"
+
+// Flags
+// 3-bit flags field of IPv4 header
+// bit 0 (0x04)	reserved, must be zero ("evil bit" in RFC 3514)
+// bit 1 (0x02)	DF, don't fragment
+// bit 2 (0x01)	MF, more fragments
+type Flags uint8
+
+const (
+	FlagMoreFragments Flags = 0x01
+	FlagDontFragment  Flags = 0x02
+	FlagReserved      Flags = 0x04
+
+	flagsMask = FlagReserved | FlagDontFragment | FlagMoreFragments
+)
+
+var flagsNames = []struct {
+	flag Flags
+	name string
+}{
+	{flag: FlagReserved, name: "RESERVED"},
+	{flag: FlagDontFragment, name: "DF"},
+	{flag: FlagMoreFragments, name: "MF"},
+}
+
+func (f Flags) Has(flag Flags) bool {
+	return f&flag == flag
+}
+
+// Set
+// returns flags with flag set
+func (f Flags) Set(flag Flags) Flags {
+	return (f | flag) & flagsMask
+}
+
+// Clear
+// returns flags with flag cleared
+func (f Flags) Clear(flag Flags) Flags {
+	return (f &^ flag) & flagsMask
+}
+
+// With
+// returns flags with flag set if value is true or cleared otherwise
+func (f Flags) With(flag Flags, value bool) Flags {
+	if value {
+		return f.Set(flag)
+	}
+
+	return f.Clear(flag)
+}
+
+func (f Flags) String() string {
+	names := make([]string, 0, len(flagsNames))
+
+	for _, n := range flagsNames {
+		if f.Has(n.flag) {
+			names = append(names, n.name)
+		}
+	}
+
+	if len(names) == 0 {
+		return "none"
+	}
+
+	return strings.Join(names, " ")
+}
@@ -0,0 +1,168 @@
+// Copyright 2026
+// license that can be found in the LICENSE file.
+
+package v4
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/name212/netpacket"
+)
+
+var ErrFragmentationNeeded = errors.New("fragmentation needed")
+
+// FragmentationNeededError
+// returned by Fragment if packet does not fit into MTU but DF flag is set
+// Contains data for ICMP "fragmentation needed and DF set" message
+type FragmentationNeededError struct {
+	// MTU
+	// next-hop MTU passed to Fragment
+	MTU int
+	// PacketLength
+	// total length of packet which cannot be fragmented
+	PacketLength int
+	// Header
+	// header of packet which cannot be fragmented
+	Header *Header
+}
+
+func (e *FragmentationNeededError) Error() string {
+	return fmt.Sprintf(
+		"%s: packet length %d exceeds MTU %d and DF flag is set",
+		ErrFragmentationNeeded,
+		e.PacketLength,
+		e.MTU,
+	)
+}
+
+func (e *FragmentationNeededError) Is(target error) bool {
+	return target == ErrFragmentationNeeded
+}
+
+// Fragment
+// splits packet into fragments which total length does not exceed mtu
+// returns packet as is if packet fits into mtu
+// Fragments payload is split on 8 bytes boundaries. All options are kept in first fragment,
+// other fragments contain only options with copied bit set
+// Fragment can split already fragmented packet, offsets are kept relative to original packet
+// Returns *FragmentationNeededError if packet does not fit into mtu and DF flag is set
+// Fragments headers are copied from packet header with fixed lengths and recomputed checksums
+// Fragments payload are copied from packet payload
+func Fragment(packet *Packet, mtu int) ([]*Packet, error) {
+	header := packet.GetHeader()
+	payload := packet.GetPayload()
+
+	payloadLen := header.GetTotalLen() - header.HeaderLen()
+	if payloadLen >= 0 && payloadLen < len(payload) {
+		payload = payload[:payloadLen]
+	}
+
+	packetLen := header.HeaderLen() + len(payload)
+	if packetLen <= mtu {
+		return []*Packet{packet}, nil
+	}
+
+	if header.DontFragment() {
+		return nil, &FragmentationNeededError{
+			MTU:          mtu,
+			PacketLength: packetLen,
+			Header:       header,
+		}
+	}
+
+	copiedOptions, err := fragmentOptions(header)
+	if err != nil {
+		return nil, err
+	}
+
+	firstChunkLen := fragmentChunkLen(mtu, header.HeaderLen())
+	otherChunkLen := fragmentChunkLen(mtu, minHeaderLength+len(copiedOptions))
+
+	if firstChunkLen <= 0 || otherChunkLen <= 0 {
+		return nil, netpacket.WrapCannotSerializeErr(
+			fmt.Errorf("MTU %d too small for IPv4 fragment with header length %d", mtu, header.HeaderLen()),
+		)
+	}
+
+	baseOffset := int(header.FragmentOffset) * fragmentOffsetUnit
+	moreFragments := header.MoreFragments()
+
+	res := make([]*Packet, 0, len(payload)/otherChunkLen+1)
+
+	for start := 0; start < len(payload); {
+		fragmentHeader := header.Clone()
+		chunkLen := firstChunkLen
+
+		if start > 0 {
+			fragmentHeader.Options = copiedOptions
+			chunkLen = otherChunkLen
+		}
+
+		end := min(start+chunkLen, len(payload))
+
+		fragmentHeader.FragmentOffset = uint16((baseOffset + start) / fragmentOffsetUnit)
+		fragmentHeader.SetMoreFragments(end < len(payload) || moreFragments)
+
+		fragment, err := newSerializedPacket(fragmentHeader, payload[start:end])
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, fragment)
+		start = end
+	}
+
+	return res, nil
+}
+
+// newSerializedPacket
+// serializes header with payload and returns packet which aliases serialized data
+// Packet is not parsed again, so header flags are kept as is
+func newSerializedPacket(header *Header, payload []byte) (*Packet, error) {
+	data, err := NewPacket(header, payload).Serialize(netpacket.SerializeOptions{
+		FixLengths:       true,
+		ComputeChecksums: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// header lengths and checksum are fixed during serialization
+	headerLen := header.HeaderLen()
+
+	return &Packet{
+		header:     header,
+		headerData: data[:headerLen],
+		payload:    data[headerLen:],
+	}, nil
+}
+
+// fragmentChunkLen
+// returns max payload length of fragment aligned on 8 bytes
+func fragmentChunkLen(mtu int, headerLen int) int {
+	return (mtu - headerLen) / fragmentOffsetUnit * fragmentOffsetUnit
+}
+
+// fragmentOptions
+// returns encoded options with copied bit set
+func fragmentOptions(header *Header) ([]byte, error) {
+	opts, err := header.ParseOptions()
+	if err != nil {
+		return nil, netpacket.WrapCannotSerializeErr(err)
+	}
+
+	copied := make([]Option, 0, len(opts))
+
+	for _, opt := range opts {
+		if opt.GetType().Copied() {
+			copied = append(copied, opt)
+		}
+	}
+
+	if len(copied) == 0 {
+		return nil, nil
+	}
+
+	return EncodeOptions(copied)
+}
@@ -7,9 +7,11 @@ import (
 	"encoding/binary"
 	"fmt"
 	"net"
+	"slices"
 	"strings"
 
 	"github.com/name212/netpacket"
+	"github.com/name212/netpacket/checksum"
 	stringsutils "github.com/name212/netpacket/utils/strings"
 )
 
@@ -49,7 +51,7 @@ type Header struct {
 	ToS            uint8
 	TotalLength    uint16
 	Identification uint16
-	Flags          uint8
+	Flags          Flags
 	FragmentOffset uint16
 	TTL            uint8
 	Protocol       uint8
@@ -57,34 +59,71 @@ type Header struct {
 	SourceIP       net.IP
 	DestinationIP  net.IP
 	Options        []byte
-
-	flags Flags
-}
-
-type Flags struct {
-	// IsEvil
-	// first bit of flags. For correct packet should be set to 0 always
-	// ParsePacket check this flag before return Header
-	IsEvil        bool
-	DontFragment  bool
-	MoreFragments bool
 }
 
 // ParseHeader parses the IPv4 header from the given byte slice
 // ParseHeader save slices from data. You should copy data before parse
 // to avoid hold full data in memory
 func ParseHeader(data []byte) (*Header, error) {
-	if err := isValidPacket(data); err != nil {
+	header := &Header{}
+
+	if err := ParseHeaderInto(data, header); err != nil {
 		return nil, err
 	}
 
-	header := &Header{
+	return header, nil
+}
+
+// ParseHeaderWithOptions
+// same as ParseHeader but applies additional parsing options
+// With opts.Copy returned header does not alias data
+// With opts.VerifyChecksum returns ErrBadChecksum error if header checksum is invalid,
+// not computed (zero) checksum is not rejected. See ChecksumStatus
+// With lenient opts.Policy header with violations which do not prevent parsing is not rejected,
+// use ParsePacketWithOptions to get anomalies list
+func ParseHeaderWithOptions(data []byte, opts netpacket.ParseOptions) (*Header, error) {
+	header := &Header{}
+
+	if err := parseHeaderInto(data, opts, header, nil); err != nil {
+		return nil, err
+	}
+
+	if opts.VerifyChecksum {
+		if err := header.verifyDataChecksum(data); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.Copy {
+		return header.Clone(), nil
+	}
+
+	return header, nil
+}
+
+// ParseHeaderInto
+// same as ParseHeader but fills passed header without heap allocations
+// All header fields are overwritten. On error header content is undefined
+// Use it with reused Header for hot paths
+func ParseHeaderInto(data []byte, header *Header) error {
+	return parseHeaderInto(data, netpacket.ParseOptions{}, header, nil)
+}
+
+// parseHeaderInto
+// parses header with policy from opts. With lenient policy violations are added to anomalies
+// Violations which do not depend on policy are added to anomalies always
+func parseHeaderInto(data []byte, opts netpacket.ParseOptions, header *Header, anomalies *netpacket.Anomalies) error {
+	if err := isValidPacket(data); err != nil {
+		return err
+	}
+
+	*header = Header{
 		Version:        data[0] >> 4,
 		IHL:            extractHeaderWordsLen(data),
 		ToS:            data[1],
 		TotalLength:    binary.BigEndian.Uint16(data[2:4]),
 		Identification: binary.BigEndian.Uint16(data[4:6]),
-		Flags:          data[6] >> 5,
+		Flags:          Flags(data[6] >> 5),
 		FragmentOffset: binary.BigEndian.Uint16(data[6:8]) & 0x1FFF,
 		TTL:            data[8],
 		Protocol:       data[9],
@@ -94,38 +133,55 @@ func ParseHeader(data []byte) (*Header, error) {
 	}
 
 	if !header.IsValidVersion() {
-		return nil, fmt.Errorf("invalid version %d", header.Version)
+		err := netpacket.NewInvalidFieldErr(Kind, "version", 0, "4", int(header.Version))
+		if err := opts.Violation(anomalies, netpacket.AnomalyBadVersion, err); err != nil {
+			return err
+		}
 	}
 
 	headerLengthBytes := header.HeaderLen()
 
 	if header.TotalLength < minHeaderLength {
-		return nil, fmt.Errorf("invalid (too small) IP total length (%d < %d)", header.TotalLength, minHeaderLength)
+		err := netpacket.NewInvalidFieldErr(Kind, "total length", 2, fmt.Sprintf(">= %d", minHeaderLength), header.GetTotalLen())
+		if err := opts.Violation(anomalies, netpacket.AnomalyInvalidLength, err); err != nil {
+			return err
+		}
 	}
 
 	if headerLengthBytes < minHeaderLength {
-		return nil, fmt.Errorf("invalid (too small) IP header length (%d < %d)", headerLengthBytes, minHeaderLength)
+		return netpacket.NewInvalidFieldErr(Kind, "header length", 0, fmt.Sprintf(">= %d", minHeaderLength), headerLengthBytes)
 	}
 
 	if headerLengthBytes > header.GetTotalLen() {
-		return nil, fmt.Errorf("invalid IP header length > IP length (%d > %d)", headerLengthBytes, header.TotalLength)
+		err := netpacket.NewInvalidFieldErr(Kind, "header length", 0, fmt.Sprintf("<= total length %d", header.TotalLength), headerLengthBytes)
+		if err := opts.Violation(anomalies, netpacket.AnomalyInvalidLength, err); err != nil {
+			return err
+		}
 	}
 
-	flags := parseFlags(header.Flags)
-
-	if flags.IsEvil {
-		return nil, fmt.Errorf("invalid IP header flags first bit set to 1")
+	if header.Flags.Has(FlagReserved) {
+		err := netpacket.NewInvalidFieldErr(Kind, "reserved flag", 6, "0", 1)
+		if err := opts.Violation(anomalies, netpacket.AnomalyReservedBits, err); err != nil {
+			return err
+		}
 	}
 
-	header.flags = flags
-
-	if headerLengthBytes > 20 && len(data) >= headerLengthBytes {
-		header.Options = data[20:headerLengthBytes]
-	} else {
+	switch {
+	case headerLengthBytes <= minHeaderLength:
+		header.Options = nil
+	case len(data) >= headerLengthBytes:
+		header.Options = data[minHeaderLength:headerLengthBytes]
+	default:
 		header.Options = nil
+		anomalies.Add(netpacket.Anomaly{
+			Code:    netpacket.AnomalyTruncatedOptions,
+			Layer:   Kind,
+			Offset:  minHeaderLength,
+			Message: fmt.Sprintf("IP header length %d exceeds data length %d", headerLengthBytes, len(data)),
+		})
 	}
 
-	return header, nil
+	return nil
 }
 
 func (h *Header) IsValidVersion() bool {
@@ -161,7 +217,40 @@ func (h *Header) GetProtocol() Protocol {
 }
 
 func (h *Header) GetFlags() Flags {
-	return h.flags
+	return h.Flags
+}
+
+// IsEvil
+// returns true if reserved flag is set. See FlagReserved
+func (h *Header) IsEvil() bool {
+	return h.Flags.Has(FlagReserved)
+}
+
+func (h *Header) DontFragment() bool {
+	return h.Flags.Has(FlagDontFragment)
+}
+
+func (h *Header) MoreFragments() bool {
+	return h.Flags.Has(FlagMoreFragments)
+}
+
+// SetDontFragment
+// sets or clears DF flag, other flags are kept
+func (h *Header) SetDontFragment(value bool) {
+	h.Flags = h.Flags.With(FlagDontFragment, value)
+}
+
+// SetMoreFragments
+// sets or clears MF flag, other flags are kept
+func (h *Header) SetMoreFragments(value bool) {
+	h.Flags = h.Flags.With(FlagMoreFragments, value)
+}
+
+// SetEvil
+// sets or clears reserved flag, other flags are kept
+// Header with reserved flag can be parsed only with lenient parse policy
+func (h *Header) SetEvil(value bool) {
+	h.Flags = h.Flags.With(FlagReserved, value)
 }
 
 func (h *Header) ProtocolString() string {
@@ -190,11 +279,14 @@ func (h *Header) String() string {
 	s.WriteString(stringsutils.FmtLn("Destination: %s", h.DestinationIP.String()))
 	s.WriteString(stringsutils.FmtLn("Protocol: %s", h.ProtocolString()))
 	s.WriteString(stringsutils.FmtLn("TTL: %d", h.TTL))
+	s.WriteString(stringsutils.FmtLn("DSCP: %s", h.GetDSCP().String()))
+	s.WriteString(stringsutils.FmtLn("ECN: %s", h.GetECN().String()))
 	s.WriteString(stringsutils.FmtLn("Header Size: %d", h.HeaderLen()))
 	s.WriteString(stringsutils.FmtLn("Packet Size: %d", h.TotalLength))
 	s.WriteString(stringsutils.FmtLn("Flags:"))
-	s.WriteString(stringsutils.FmtLnWithTabPrefix("Don't Fragment: %v", flags.DontFragment))
-	s.WriteString(stringsutils.FmtLnWithTabPrefix("More Fragments: %v", flags.MoreFragments))
+	s.WriteString(stringsutils.FmtLnWithTabPrefix("Reserved: %v", flags.Has(FlagReserved)))
+	s.WriteString(stringsutils.FmtLnWithTabPrefix("Don't Fragment: %v", flags.Has(FlagDontFragment)))
+	s.WriteString(stringsutils.FmtLnWithTabPrefix("More Fragments: %v", flags.Has(FlagMoreFragments)))
 	h.writeOptions(&s)
 	s.WriteString(fmt.Sprintf("Checksum: %d", h.Checksum))
 
@@ -205,6 +297,266 @@ func (h *Header) ParseOptions() ([]Option, error) {
 	return parseOptions(h.Options)
 }
 
+// ComputeChecksum
+// calculates header checksum over encoded header fields and options
+// Checksum field is treated as zero during calculation
+// Use Packet.ComputeChecksum for parsed packets to calculate checksum over original header bytes
+func (h *Header) ComputeChecksum() uint16 {
+	var fixedPart [minHeaderLength]byte
+
+	h.putFixedPart(fixedPart[:])
+	// checksum field
+	fixedPart[10] = 0
+	fixedPart[11] = 0
+
+	sum := checksum.Sum(fixedPart[:], 0)
+	sum = checksum.Sum(h.Options, sum)
+
+	return checksum.Fold(sum)
+}
+
+// VerifyChecksum
+// returns true if Checksum field equal to computed checksum
+// Use ChecksumStatus to distinguish offloaded checksum calculation from corrupted header
+func (h *Header) VerifyChecksum() bool {
+	return h.ChecksumStatus() == checksum.StatusValid
+}
+
+// ChecksumStatus
+// verifies Checksum field with checksum calculated by ComputeChecksum
+// checksum.StatusNotComputed returns if Checksum field is zero and does not match computed checksum.
+// Zero checksum on locally sent packets often means that checksum calculation was offloaded to NIC
+// checksum.StatusInvalid returns for other mismatches, for example for corrupted header
+func (h *Header) ChecksumStatus() checksum.Status {
+	return headerChecksumStatus(h.Checksum, h.ComputeChecksum())
+}
+
+func headerChecksumStatus(actual, computed uint16) checksum.Status {
+	switch {
+	case actual == computed:
+		return checksum.StatusValid
+	case actual == 0:
+		return checksum.StatusNotComputed
+	default:
+		return checksum.StatusInvalid
+	}
+}
+
+// computeDataChecksum
+// calculates checksum over original header bytes if data contains full header
+// otherwise falls back to ComputeChecksum
+func (h *Header) computeDataChecksum(data []byte) uint16 {
+	headerLengthBytes := h.HeaderLen()
+	if headerLengthBytes < minHeaderLength || len(data) < headerLengthBytes {
+		return h.ComputeChecksum()
+	}
+
+	// skip checksum field
+	sum := checksum.Sum(data[:10], 0)
+	sum = checksum.Sum(data[12:headerLengthBytes], sum)
+
+	return checksum.Fold(sum)
+}
+
+// verifyDataChecksum
+// returns ErrBadChecksum error if checksum over original header bytes is invalid
+// Not computed (zero) checksum is not rejected because it is NIC offload artifact, not corruption
+func (h *Header) verifyDataChecksum(data []byte) error {
+	computed := h.computeDataChecksum(data)
+
+	if status := headerChecksumStatus(h.Checksum, computed); status == checksum.StatusInvalid {
+		return h.wrapBadChecksumErr(computed, status)
+	}
+
+	return nil
+}
+
+// Clone
+// returns deep copy of header which does not alias parsed data
+func (h *Header) Clone() *Header {
+	res := *h
+	res.SourceIP = slices.Clone(h.SourceIP)
+	res.DestinationIP = slices.Clone(h.DestinationIP)
+	res.Options = slices.Clone(h.Options)
+
+	return &res
+}
+
+// SetOptions
+// encodes options with EOOL padding and sets Options and IHL
+func (h *Header) SetOptions(opts []Option) error {
+	encoded, err := EncodeOptions(opts)
+	if err != nil {
+		return err
+	}
+
+	h.setOptionsData(encoded)
+
+	return nil
+}
+
+// SetOptionsData
+// sets raw options bytes and IHL. Data length should be multiple of 4 and not exceed 40 bytes
+func (h *Header) SetOptionsData(data []byte) error {
+	if err := validateOptionsLen(len(data)); err != nil {
+		return err
+	}
+
+	h.setOptionsData(data)
+
+	return nil
+}
+
+func (h *Header) setOptionsData(data []byte) {
+	if len(data) == 0 {
+		data = nil
+	}
+
+	h.Options = data
+	h.IHL = uint8((minHeaderLength + len(data)) / 4)
+}
+
+// FixLengths
+// sets IHL from options length and TotalLength from header and payload lengths
+func (h *Header) FixLengths(payloadLen int) error {
+	if err := validateOptionsLen(len(h.Options)); err != nil {
+		return err
+	}
+
+	headerLengthBytes := minHeaderLength + len(h.Options)
+	totalLen := headerLengthBytes + payloadLen
+
+	if totalLen > maxTotalLength {
+		return netpacket.WrapCannotSerializeErr(
+			fmt.Errorf("IPv4 total length %d exceeds %d", totalLen, maxTotalLength),
+		)
+	}
+
+	h.IHL = uint8(headerLengthBytes / 4)
+	h.TotalLength = uint16(totalLen)
+
+	return nil
+}
+
+// AppendTo
+// appends header bytes to buf as is without fixing lengths and checksum
+// IHL should correspond to options length
+func (h *Header) AppendTo(buf []byte) ([]byte, error) {
+	if err := h.validateForSerialize(); err != nil {
+		return nil, err
+	}
+
+	start := len(buf)
+	buf = slices.Grow(buf, h.HeaderLen())
+	buf = buf[:start+minHeaderLength]
+	h.putFixedPart(buf[start:])
+
+	return append(buf, h.Options...), nil
+}
+
+// MarshalBinary
+// returns header bytes. See AppendTo
+func (h *Header) MarshalBinary() ([]byte, error) {
+	return h.AppendTo(make([]byte, 0, minHeaderLength+len(h.Options)))
+}
+
+// PseudoHeader
+// returns pseudo-header for checksums of transport layer carried by packet
+// netpacket.SerializeLayers passes it to upper layers
+func (h *Header) PseudoHeader() *checksum.IPv4PseudoHeader {
+	return &checksum.IPv4PseudoHeader{
+		Source:      h.SourceIP,
+		Destination: h.DestinationIP,
+		Protocol:    h.Protocol,
+	}
+}
+
+// SerializeTo
+// prepends header to buffer. Current buffer bytes are treated as payload
+// With opts.FixLengths sets IHL and TotalLength
+// With opts.ComputeChecksums sets Checksum
+// Header fields are changed in place
+func (h *Header) SerializeTo(b *netpacket.SerializeBuffer, opts netpacket.SerializeOptions) error {
+	if opts.FixLengths {
+		if err := h.FixLengths(len(b.Bytes())); err != nil {
+			return err
+		}
+	}
+
+	if opts.ComputeChecksums {
+		h.Checksum = h.ComputeChecksum()
+	}
+
+	if err := h.validateForSerialize(); err != nil {
+		return err
+	}
+
+	bytes, err := b.PrependBytes(h.HeaderLen())
+	if err != nil {
+		return err
+	}
+
+	h.putFixedPart(bytes)
+	copy(bytes[minHeaderLength:], h.Options)
+
+	return nil
+}
+
+func (h *Header) wrapBadChecksumErr(computed uint16, status checksum.Status) error {
+	return netpacket.WrapBadChecksumErr(
+		fmt.Errorf("IPv4 header checksum %d, computed %d: %s", h.Checksum, computed, status),
+	)
+}
+
+func (h *Header) validateForSerialize() error {
+	if err := validateOptionsLen(len(h.Options)); err != nil {
+		return err
+	}
+
+	if h.HeaderLen() != minHeaderLength+len(h.Options) {
+		return netpacket.WrapCannotSerializeErr(
+			fmt.Errorf("IPv4 header length %d does not match options length %d", h.HeaderLen(), len(h.Options)),
+		)
+	}
+
+	if h.SourceIP.To4() == nil || h.DestinationIP.To4() == nil {
+		return netpacket.WrapCannotSerializeErr(
+			fmt.Errorf("IPv4 header source %v or destination %v is not IPv4 address", h.SourceIP, h.DestinationIP),
+		)
+	}
+
+	return nil
+}
+
+func validateOptionsLen(optionsLen int) error {
+	if optionsLen%4 != 0 {
+		return netpacket.WrapCannotSerializeErr(
+			fmt.Errorf("IPv4 options length %d is not multiple of 4", optionsLen),
+		)
+	}
+
+	if optionsLen > maxOptionsLength {
+		return netpacket.WrapCannotSerializeErr(
+			fmt.Errorf("IPv4 options length %d exceeds %d", optionsLen, maxOptionsLength),
+		)
+	}
+
+	return nil
+}
+
+func (h *Header) putFixedPart(b []byte) {
+	b[0] = h.Version<<4 | h.IHL&0x0F
+	b[1] = h.ToS
+	binary.BigEndian.PutUint16(b[2:4], h.TotalLength)
+	binary.BigEndian.PutUint16(b[4:6], h.Identification)
+	binary.BigEndian.PutUint16(b[6:8], uint16(h.Flags&0x07)<<13|h.FragmentOffset&0x1FFF)
+	b[8] = h.TTL
+	b[9] = h.Protocol
+	binary.BigEndian.PutUint16(b[10:12], h.Checksum)
+	copy(b[12:16], h.SourceIP.To4())
+	copy(b[16:20], h.DestinationIP.To4())
+}
+
 func (h *Header) writeOptions(s *strings.Builder) {
 	if h.Options == nil {
 		s.WriteString(stringsutils.FmtLn("No options set"))
@@ -237,17 +589,3 @@ func headerLen(words uint8) int {
 func extractHeaderWordsLen(data []byte) uint8 {
 	return data[0] & 0x0F
 }
-
-const bitSet = 1
-
-func parseFlags(f uint8) Flags {
-	evil := (f >> 0) == bitSet
-	df := (f >> 1) == bitSet
-	mf := (f >> 2) == bitSet
-
-	return Flags{
-		IsEvil:        evil,
-		DontFragment:  df,
-		MoreFragments: mf,
-	}
-}
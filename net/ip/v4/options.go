@@ -4,12 +4,16 @@
 package v4
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
+	"github.com/name212/netpacket"
 	stringsutils "github.com/name212/netpacket/utils/strings"
 )
 
+var ErrWrongOptionType = errors.New("wrong option type")
+
 type OptionType uint8
 
 // 0/0x00	EOOL	End of Option List
@@ -75,10 +79,59 @@ const (
 	OptionRFC3692StyleExperimentFour   OptionType = 222
 )
 
+const (
+	optionCopiedBit  = 0x80
+	optionClassShift = 5
+	optionClassMask  = 0x03
+	optionNumberMask = 0x1F
+)
+
+// OptionClass
+// 0	control
+// 1	reserved for future use
+// 2	debugging and measurement
+// 3	reserved for future use
+type OptionClass uint8
+
+const (
+	OptionClassControl                 OptionClass = 0
+	OptionClassReservedFirst           OptionClass = 1
+	OptionClassDebuggingAndMeasurement OptionClass = 2
+	OptionClassReservedSecond          OptionClass = 3
+)
+
+func (c OptionClass) String() string {
+	switch c {
+	case OptionClassControl:
+		return "control"
+	case OptionClassDebuggingAndMeasurement:
+		return "debugging and measurement"
+	default:
+		return "reserved"
+	}
+}
+
+// Copied
+// returns true if option should be copied into all fragments
+func (t OptionType) Copied() bool {
+	return t&optionCopiedBit != 0
+}
+
+func (t OptionType) Class() OptionClass {
+	return OptionClass(t >> optionClassShift & optionClassMask)
+}
+
+func (t OptionType) Number() uint8 {
+	return uint8(t & optionNumberMask)
+}
+
 type Option struct {
 	typeID uint8
 	length uint8
 	data   []byte
+	// offset
+	// offset of option from IPv4 header start. Zero for created options
+	offset int
 }
 
 func parseOptions(data []byte) ([]Option, error) {
@@ -87,9 +140,10 @@ func parseOptions(data []byte) ([]Option, error) {
 	}
 
 	res := make([]Option, 0, 4)
+	offset := minHeaderLength
 
 	for len(data) > 0 {
-		opt := Option{typeID: data[0]}
+		opt := Option{typeID: data[0], offset: offset}
 
 		switch opt.GetType() {
 		case OptionEndOfList:
@@ -97,21 +151,29 @@ func parseOptions(data []byte) ([]Option, error) {
 		case OptionNoOperation:
 			opt.length = 1
 			data = data[1:]
+			offset++
 			res = append(res, opt)
 		default:
 			if len(data) < 2 {
-				return nil, opt.wrapError("invalid length. Length %d less than 2", len(data))
+				return nil, netpacket.NewShortDataErr(Kind, opt.fieldName("data length"), offset, ">= 2", len(data))
 			}
 			opt.length = data[1]
 			intLen := opt.GetLength()
 			if len(data) < intLen {
-				return nil, opt.wrapError("length exceeds remaining IP header size, length %v", intLen)
+				return nil, netpacket.NewShortDataErr(
+					Kind,
+					opt.fieldName("length"),
+					offset+1,
+					fmt.Sprintf("<= remaining header length %d", len(data)),
+					intLen,
+				)
 			}
 			if intLen <= 2 {
-				return nil, opt.wrapError("invalid length %d. Must be greater than 2", intLen)
+				return nil, netpacket.NewInvalidFieldErr(Kind, opt.fieldName("length"), offset+1, "> 2", intLen)
 			}
 			opt.data = data[2:intLen]
 			data = data[intLen:]
+			offset += intLen
 			res = append(res, opt)
 		}
 	}
@@ -143,7 +205,44 @@ func (o *Option) TypeLong() string {
 	return getOptionDescription(o.GetType()).long
 }
 
+// AppendTo
+// appends option bytes to buf
+func (o *Option) AppendTo(buf []byte) []byte {
+	switch o.GetType() {
+	case OptionEndOfList, OptionNoOperation:
+		return append(buf, o.typeID)
+	default:
+		buf = append(buf, o.typeID, o.length)
+		return append(buf, o.data...)
+	}
+}
+
+// writeTypedData
+// writes decoded option data. Returns false if option is not known or cannot be decoded
+func (o *Option) writeTypedData(b *strings.Builder) bool {
+	switch o.GetType() {
+	case OptionRecordRoute, OptionLooseSourceRoute, OptionStrictSourceRoute:
+		return o.writeRoute(b)
+	case OptionTimeStamp:
+		return o.writeTimestamp(b)
+	case OptionCommercialIPSecurityOption:
+		return o.writeCIPSO(b)
+	case OptionSecurityRIPSO:
+		return o.writeRIPSO(b)
+	case OptionExtendedSecurityRIPSO:
+		return o.writeExtendedSecurity(b)
+	case OptionRouterAlert, OptionQuickStart, OptionStreamID, OptionMTUProbe, OptionMTUReply, OptionTraceroute:
+		return o.writeFixed(b)
+	default:
+		return false
+	}
+}
+
 func (o *Option) writeData(b *strings.Builder) {
+	if o.writeTypedData(b) {
+		return
+	}
+
 	data := o.GetData()
 	if len(data) == 0 {
 		b.WriteString("\tNo data")
@@ -160,12 +259,128 @@ func (o *Option) String() string {
 	b.WriteString(stringsutils.FmtLn("Option:"))
 	b.WriteString(stringsutils.FmtLnWithTabPrefix("Type: %s", o.TypeShortWithID()))
 	b.WriteString(stringsutils.FmtLnWithTabPrefix("Type description: %s", o.TypeLong()))
+	b.WriteString(stringsutils.FmtLnWithTabPrefix("Copied: %v", o.GetType().Copied()))
+	b.WriteString(stringsutils.FmtLnWithTabPrefix("Class: %s(%d)", o.GetType().Class().String(), o.GetType().Class()))
+	b.WriteString(stringsutils.FmtLnWithTabPrefix("Number: %d", o.GetType().Number()))
 	b.WriteString(stringsutils.FmtLnWithTabPrefix("Full Length: %d", o.GetLength()))
 	o.writeData(&b)
 
 	return b.String()
 }
 
+func NewEndOfListOption() Option {
+	return Option{typeID: uint8(OptionEndOfList), length: 1}
+}
+
+func NewNOPOption() Option {
+	return Option{typeID: uint8(OptionNoOperation), length: 1}
+}
+
+// NewOption
+// creates option with type and raw data. Length is calculated from data
+// Data is validated for option types with typed view
+func NewOption(optionType OptionType, data []byte) (Option, error) {
+	if optionType == OptionEndOfList || optionType == OptionNoOperation {
+		if len(data) > 0 {
+			opt := Option{typeID: uint8(optionType)}
+			return Option{}, opt.wrapError("should not contain data")
+		}
+
+		return Option{typeID: uint8(optionType), length: 1}, nil
+	}
+
+	opt := newOption(optionType, data)
+	if len(data)+2 > maxOptionsLength {
+		return Option{}, opt.wrapError("data length %d too long", len(data))
+	}
+
+	if err := opt.validate(); err != nil {
+		return Option{}, err
+	}
+
+	return opt, nil
+}
+
+// EncodeOptions
+// encodes options to header bytes and adds EOOL padding to 32-bit alignment
+// returns ErrCannotSerialize error if encoded options exceed 40 bytes
+func EncodeOptions(opts []Option) ([]byte, error) {
+	res := make([]byte, 0, maxOptionsLength)
+
+	for i := range opts {
+		res = opts[i].AppendTo(res)
+	}
+
+	for len(res)%4 != 0 {
+		res = append(res, uint8(OptionEndOfList))
+	}
+
+	if err := validateOptionsLen(len(res)); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func newOption(optionType OptionType, data []byte) Option {
+	return Option{
+		typeID: uint8(optionType),
+		length: uint8(len(data) + 2),
+		data:   data,
+	}
+}
+
+// validate
+// decodes option with typed view to check data
+func (o *Option) validate() error {
+	var err error
+
+	switch o.GetType() {
+	case OptionRecordRoute, OptionLooseSourceRoute, OptionStrictSourceRoute:
+		_, err = o.Route()
+	case OptionTimeStamp:
+		_, err = o.Timestamp()
+	case OptionCommercialIPSecurityOption:
+		_, err = o.CIPSO()
+	case OptionSecurityRIPSO:
+		_, err = o.RIPSO()
+	case OptionExtendedSecurityRIPSO:
+		_, err = o.ExtendedSecurity()
+	case OptionRouterAlert:
+		_, err = o.RouterAlert()
+	case OptionQuickStart:
+		_, err = o.QuickStart()
+	case OptionStreamID:
+		_, err = o.StreamID()
+	case OptionMTUProbe, OptionMTUReply:
+		_, err = o.MTU()
+	case OptionTraceroute:
+		_, err = o.Traceroute()
+	}
+
+	return err
+}
+
+func (o *Option) assertType(optionType OptionType) error {
+	if o.GetType() != optionType {
+		return fmt.Errorf("%w: %s is not %s", ErrWrongOptionType, o.TypeShortWithID(), getOptionDescription(optionType).short)
+	}
+
+	return nil
+}
+
+// fieldName
+// returns name of option field for ParseError
+func (o *Option) fieldName(field string) string {
+	return fmt.Sprintf("option %s %s", o.TypeShortWithID(), field)
+}
+
+// invalidFieldErr
+// returns ParseError for option field at fieldOffset from option start
+func (o *Option) invalidFieldErr(field string, fieldOffset int, expected string, actual int) error {
+	return netpacket.NewInvalidFieldErr(Kind, o.fieldName(field), o.offset+fieldOffset, expected, actual)
+}
+
 func (o *Option) wrapError(f string, args ...any) error {
 	f = fmt.Sprintf("option %s: ", o.TypeShortWithID()) + f
 	return fmt.Errorf(f, args...)
@@ -302,7 +517,13 @@ var optionTypesMap = map[OptionType]*optionDescription{
 func unknownOptionDescription(optionType OptionType) *optionDescription {
 	return &optionDescription{
 		short: "UNKNOWN",
-		long:  fmt.Sprintf("Unknown: %d", optionType),
+		long: fmt.Sprintf(
+			"Unknown: %d (copied %v, class %s, number %d)",
+			optionType,
+			optionType.Copied(),
+			optionType.Class().String(),
+			optionType.Number(),
+		),
 	}
 }
 
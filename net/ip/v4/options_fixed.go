@@ -0,0 +1,279 @@
+// Copyright 2026
+// license that can be found in the LICENSE file.
+
+package v4
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	stringsutils "github.com/name212/netpacket/utils/strings"
+)
+
+const (
+	routerAlertOptionLength = 4
+	quickStartOptionLength  = 8
+	streamIDOptionLength    = 4
+	mtuOptionLength         = 4
+	tracerouteOptionLength  = 12
+
+	quickStartNonceShift = 2
+	// quickStartBaseRateKbps
+	// rate request 1 corresponds to 80 Kbps, each next value doubles rate
+	quickStartBaseRateKbps = 40
+)
+
+// RouterAlertValue
+// 0	router shall examine packet
+// 1-65535	reserved or assigned by IANA (for example aggregated RSVP reservation levels)
+type RouterAlertValue uint16
+
+const RouterAlertExaminePacket RouterAlertValue = 0
+
+// QuickStartFunction
+// 0	rate request
+// 8	report of approved rate
+type QuickStartFunction uint8
+
+const (
+	QuickStartRateRequest QuickStartFunction = 0
+	QuickStartRateReport  QuickStartFunction = 8
+)
+
+func (f QuickStartFunction) String() string {
+	switch f {
+	case QuickStartRateRequest:
+		return "rate request"
+	case QuickStartRateReport:
+		return "rate report"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(f))
+	}
+}
+
+// QuickStartOption
+// typed view of Quick-Start option (RFC 4782)
+type QuickStartOption struct {
+	Function QuickStartFunction
+	// Rate
+	// rate request or rate report field from 0 to 15
+	Rate uint8
+	// TTL
+	// QS TTL. Not used for rate report
+	TTL uint8
+	// Nonce
+	// 30 bits QS nonce
+	Nonce uint32
+}
+
+// RateKbps
+// returns rate in Kbps. Rate 0 is 0 Kbps, other values are 40*2^Rate Kbps
+func (q *QuickStartOption) RateKbps() uint64 {
+	if q.Rate == 0 {
+		return 0
+	}
+
+	return quickStartBaseRateKbps << q.Rate
+}
+
+// TracerouteOption
+// typed view of Traceroute option (RFC 1393)
+type TracerouteOption struct {
+	ID           uint16
+	OutboundHops uint16
+	ReturnHops   uint16
+	// Originator
+	// originator IP address. Originator aliases option data
+	Originator net.IP
+}
+
+// RouterAlert
+// returns value of Router Alert option
+func (o *Option) RouterAlert() (RouterAlertValue, error) {
+	if err := o.assertTypeAndLength(OptionRouterAlert, routerAlertOptionLength); err != nil {
+		return 0, err
+	}
+
+	return RouterAlertValue(binary.BigEndian.Uint16(o.data)), nil
+}
+
+// QuickStart
+// returns typed view of Quick-Start option
+func (o *Option) QuickStart() (*QuickStartOption, error) {
+	if err := o.assertTypeAndLength(OptionQuickStart, quickStartOptionLength); err != nil {
+		return nil, err
+	}
+
+	return &QuickStartOption{
+		Function: QuickStartFunction(o.data[0] >> 4),
+		Rate:     o.data[0] & 0x0F,
+		TTL:      o.data[1],
+		Nonce:    binary.BigEndian.Uint32(o.data[2:6]) >> quickStartNonceShift,
+	}, nil
+}
+
+// StreamID
+// returns stream identifier of Stream ID option
+func (o *Option) StreamID() (uint16, error) {
+	if err := o.assertTypeAndLength(OptionStreamID, streamIDOptionLength); err != nil {
+		return 0, err
+	}
+
+	return binary.BigEndian.Uint16(o.data), nil
+}
+
+// MTU
+// returns MTU value of MTU Probe and MTU Reply options
+func (o *Option) MTU() (uint16, error) {
+	optionType := OptionMTUProbe
+	if o.GetType() == OptionMTUReply {
+		optionType = OptionMTUReply
+	}
+
+	if err := o.assertTypeAndLength(optionType, mtuOptionLength); err != nil {
+		return 0, err
+	}
+
+	return binary.BigEndian.Uint16(o.data), nil
+}
+
+// Traceroute
+// returns typed view of Traceroute option
+func (o *Option) Traceroute() (*TracerouteOption, error) {
+	if err := o.assertTypeAndLength(OptionTraceroute, tracerouteOptionLength); err != nil {
+		return nil, err
+	}
+
+	return &TracerouteOption{
+		ID:           binary.BigEndian.Uint16(o.data[0:2]),
+		OutboundHops: binary.BigEndian.Uint16(o.data[2:4]),
+		ReturnHops:   binary.BigEndian.Uint16(o.data[4:6]),
+		Originator:   net.IP(o.data[6:10]),
+	}, nil
+}
+
+func (o *Option) assertTypeAndLength(optionType OptionType, length int) error {
+	if err := o.assertType(optionType); err != nil {
+		return err
+	}
+
+	if o.GetLength() != length || len(o.data) != length-2 {
+		return o.invalidFieldErr("length", 1, strconv.Itoa(length), o.GetLength())
+	}
+
+	return nil
+}
+
+func (o *Option) writeFixed(b *strings.Builder) bool {
+	switch o.GetType() {
+	case OptionRouterAlert:
+		value, err := o.RouterAlert()
+		if err != nil {
+			return false
+		}
+
+		if value == RouterAlertExaminePacket {
+			b.WriteString(stringsutils.FmtWithTabPrefix("Value: %d (router shall examine packet)", value))
+			return true
+		}
+
+		b.WriteString(stringsutils.FmtWithTabPrefix("Value: %d", value))
+	case OptionQuickStart:
+		qs, err := o.QuickStart()
+		if err != nil {
+			return false
+		}
+
+		b.WriteString(stringsutils.FmtLnWithTabPrefix("Function: %s(%d)", qs.Function.String(), qs.Function))
+		b.WriteString(stringsutils.FmtLnWithTabPrefix("Rate: %d (%d Kbps)", qs.Rate, qs.RateKbps()))
+		if qs.Function == QuickStartRateRequest {
+			b.WriteString(stringsutils.FmtLnWithTabPrefix("QS TTL: %d", qs.TTL))
+		}
+		b.WriteString(stringsutils.FmtWithTabPrefix("QS nonce: %d", qs.Nonce))
+	case OptionStreamID:
+		id, err := o.StreamID()
+		if err != nil {
+			return false
+		}
+
+		b.WriteString(stringsutils.FmtWithTabPrefix("Stream ID: %d", id))
+	case OptionMTUProbe, OptionMTUReply:
+		mtu, err := o.MTU()
+		if err != nil {
+			return false
+		}
+
+		b.WriteString(stringsutils.FmtWithTabPrefix("MTU: %d", mtu))
+	case OptionTraceroute:
+		tr, err := o.Traceroute()
+		if err != nil {
+			return false
+		}
+
+		b.WriteString(stringsutils.FmtLnWithTabPrefix("ID: %d", tr.ID))
+		b.WriteString(stringsutils.FmtLnWithTabPrefix("Outbound hops: %d", tr.OutboundHops))
+		b.WriteString(stringsutils.FmtLnWithTabPrefix("Return hops: %d", tr.ReturnHops))
+		b.WriteString(stringsutils.FmtWithTabPrefix("Originator: %s", tr.Originator.String()))
+	default:
+		return false
+	}
+
+	return true
+}
+
+func NewRouterAlertOption(value RouterAlertValue) Option {
+	return newOption(OptionRouterAlert, binary.BigEndian.AppendUint16(nil, uint16(value)))
+}
+
+// NewQuickStartOption
+// creates Quick-Start option. Rate should be less than 16 and nonce should fit into 30 bits
+func NewQuickStartOption(qs QuickStartOption) (Option, error) {
+	opt := Option{typeID: uint8(OptionQuickStart)}
+
+	if qs.Function > 0x0F || qs.Rate > 0x0F {
+		return Option{}, opt.wrapError("function %d or rate %d exceeds %d", qs.Function, qs.Rate, 0x0F)
+	}
+
+	if qs.Nonce >= 1<<(32-quickStartNonceShift) {
+		return Option{}, opt.wrapError("nonce %d exceeds 30 bits", qs.Nonce)
+	}
+
+	data := make([]byte, 0, quickStartOptionLength-2)
+	data = append(data, uint8(qs.Function)<<4|qs.Rate, qs.TTL)
+	data = binary.BigEndian.AppendUint32(data, qs.Nonce<<quickStartNonceShift)
+
+	return NewOption(OptionQuickStart, data)
+}
+
+func NewStreamIDOption(id uint16) Option {
+	return newOption(OptionStreamID, binary.BigEndian.AppendUint16(nil, id))
+}
+
+func NewMTUProbeOption(mtu uint16) Option {
+	return newOption(OptionMTUProbe, binary.BigEndian.AppendUint16(nil, mtu))
+}
+
+func NewMTUReplyOption(mtu uint16) Option {
+	return newOption(OptionMTUReply, binary.BigEndian.AppendUint16(nil, mtu))
+}
+
+// NewTracerouteOption
+// creates Traceroute option. Originator should be IPv4 address
+func NewTracerouteOption(tr TracerouteOption) (Option, error) {
+	originator := tr.Originator.To4()
+	if originator == nil {
+		opt := Option{typeID: uint8(OptionTraceroute)}
+		return Option{}, opt.wrapError("originator %s is not IPv4 address", tr.Originator)
+	}
+
+	data := make([]byte, 0, tracerouteOptionLength-2)
+	data = binary.BigEndian.AppendUint16(data, tr.ID)
+	data = binary.BigEndian.AppendUint16(data, tr.OutboundHops)
+	data = binary.BigEndian.AppendUint16(data, tr.ReturnHops)
+	data = append(data, originator...)
+
+	return NewOption(OptionTraceroute, data)
+}
@@ -0,0 +1,190 @@
+// Copyright 2026
+// license that can be found in the LICENSE file.
+
+package v4
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	stringsutils "github.com/name212/netpacket/utils/strings"
+)
+
+const (
+	routeAddressLength = 4
+	// routeMinPointer
+	// pointer is counted from option type byte, first address starts after type, length and pointer
+	routeMinPointer = 4
+)
+
+// RouteOption
+// typed view of Record Route, Loose Source Route and Strict Source Route options
+type RouteOption struct {
+	// Pointer
+	// raw pointer field. Points to octet (counted from 1 from option type) of next address slot
+	Pointer uint8
+	// Addresses
+	// recorded or route addresses. Addresses alias option data
+	Addresses []net.IP
+}
+
+// NextIndex
+// returns index in Addresses of next address slot
+// returns -1 if route is full (pointer greater than option length)
+// or pointer does not point to address slot start
+func (r *RouteOption) NextIndex() int {
+	pointerOffset := int(r.Pointer) - routeMinPointer
+	if pointerOffset < 0 || pointerOffset%routeAddressLength != 0 {
+		return -1
+	}
+
+	index := pointerOffset / routeAddressLength
+	if index >= len(r.Addresses) {
+		return -1
+	}
+
+	return index
+}
+
+// IsFull
+// returns true if all address slots are used
+func (r *RouteOption) IsFull() bool {
+	return r.NextIndex() < 0
+}
+
+// IsRouteOption
+// returns true for Record Route, Loose Source Route and Strict Source Route options
+func (o *Option) IsRouteOption() bool {
+	switch o.GetType() {
+	case OptionRecordRoute, OptionLooseSourceRoute, OptionStrictSourceRoute:
+		return true
+	default:
+		return false
+	}
+}
+
+// Route
+// returns typed view of Record Route, Loose Source Route and Strict Source Route options
+// returns ErrWrongOptionType error for other options
+// Validates pointer and length consistency
+func (o *Option) Route() (*RouteOption, error) {
+	if !o.IsRouteOption() {
+		return nil, fmt.Errorf("%w: %s is not route option", ErrWrongOptionType, o.TypeShortWithID())
+	}
+
+	if len(o.data) < 1 {
+		return nil, o.invalidFieldErr("length", 1, ">= 3", o.GetLength())
+	}
+
+	addressesData := o.data[1:]
+	if len(addressesData)%routeAddressLength != 0 {
+		return nil, o.invalidFieldErr("length", 1, fmt.Sprintf("3 + %d*n", routeAddressLength), o.GetLength())
+	}
+
+	pointer := o.data[0]
+	if pointer < routeMinPointer {
+		return nil, o.invalidFieldErr("pointer", 2, fmt.Sprintf(">= %d", routeMinPointer), int(pointer))
+	}
+
+	if (int(pointer)-routeMinPointer)%routeAddressLength != 0 {
+		return nil, o.invalidFieldErr("pointer", 2, fmt.Sprintf("%d + %d*n", routeMinPointer, routeAddressLength), int(pointer))
+	}
+
+	if int(pointer) > o.GetLength()+1 {
+		return nil, o.invalidFieldErr("pointer", 2, fmt.Sprintf("<= %d", o.GetLength()+1), int(pointer))
+	}
+
+	addresses := make([]net.IP, 0, len(addressesData)/routeAddressLength)
+	for d := addressesData; len(d) >= routeAddressLength; d = d[routeAddressLength:] {
+		addresses = append(addresses, net.IP(d[:routeAddressLength]))
+	}
+
+	return &RouteOption{
+		Pointer:   pointer,
+		Addresses: addresses,
+	}, nil
+}
+
+func (o *Option) writeRoute(b *strings.Builder) bool {
+	route, err := o.Route()
+	if err != nil {
+		return false
+	}
+
+	b.WriteString(stringsutils.FmtLnWithTabPrefix("Pointer: %d", route.Pointer))
+
+	nextIndex := route.NextIndex()
+	if nextIndex < 0 {
+		b.WriteString(stringsutils.FmtLnWithTabPrefix("Next slot: route is full"))
+	} else {
+		b.WriteString(stringsutils.FmtLnWithTabPrefix("Next slot: %d", nextIndex))
+	}
+
+	if len(route.Addresses) == 0 {
+		b.WriteString(stringsutils.FmtWithTabPrefix("No addresses"))
+		return true
+	}
+
+	addressesStrings := make([]string, 0, len(route.Addresses))
+	for i, address := range route.Addresses {
+		addressesStrings = append(addressesStrings, fmt.Sprintf("%d: %s", i, address.String()))
+	}
+
+	b.WriteString(stringsutils.FmtLnWithTabPrefix("Addresses:"))
+	b.WriteString(stringsutils.ShiftOnTabs(strings.Join(addressesStrings, "\n"), 2))
+
+	return true
+}
+
+// NewRecordRouteOption
+// creates Record Route option with slots empty address slots
+func NewRecordRouteOption(slots int) (Option, error) {
+	return NewRouteOption(OptionRecordRoute, RouteOption{Addresses: make([]net.IP, slots)})
+}
+
+// NewLooseSourceRouteOption
+// creates Loose Source Route option with pointer to first address
+func NewLooseSourceRouteOption(addresses ...net.IP) (Option, error) {
+	return NewRouteOption(OptionLooseSourceRoute, RouteOption{Addresses: addresses})
+}
+
+// NewStrictSourceRouteOption
+// creates Strict Source Route option with pointer to first address
+func NewStrictSourceRouteOption(addresses ...net.IP) (Option, error) {
+	return NewRouteOption(OptionStrictSourceRoute, RouteOption{Addresses: addresses})
+}
+
+// NewRouteOption
+// creates Record Route, Loose Source Route or Strict Source Route option
+// Zero pointer is replaced with pointer to first address. Nil addresses are encoded as 0.0.0.0
+func NewRouteOption(optionType OptionType, route RouteOption) (Option, error) {
+	opt := Option{typeID: uint8(optionType)}
+	if !opt.IsRouteOption() {
+		return Option{}, fmt.Errorf("%w: %s is not route option", ErrWrongOptionType, opt.TypeShortWithID())
+	}
+
+	pointer := route.Pointer
+	if pointer == 0 {
+		pointer = routeMinPointer
+	}
+
+	data := make([]byte, 0, 1+len(route.Addresses)*routeAddressLength)
+	data = append(data, pointer)
+
+	for _, address := range route.Addresses {
+		if address == nil {
+			data = append(data, 0, 0, 0, 0)
+			continue
+		}
+
+		ip := address.To4()
+		if ip == nil {
+			return Option{}, opt.wrapError("address %s is not IPv4 address", address)
+		}
+
+		data = append(data, ip...)
+	}
+
+	return NewOption(optionType, data)
+}
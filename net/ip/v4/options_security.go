@@ -0,0 +1,608 @@
+// Copyright 2026
+// license that can be found in the LICENSE file.
+
+package v4
+
+import (
+	"encoding/binary"
+	"fmt"
+	"slices"
+	"strings"
+
+	stringsutils "github.com/name212/netpacket/utils/strings"
+)
+
+const (
+	cipsoDOILength = 4
+	// cipsoTagHeaderLength
+	// tag type, tag length
+	cipsoTagHeaderLength = 2
+	// cipsoLevelTagMinLength
+	// tag type, tag length, alignment octet and sensitivity level
+	cipsoLevelTagMinLength = 4
+	cipsoMaxTagLength      = 34
+	cipsoMaxCategory       = (cipsoMaxTagLength - cipsoLevelTagMinLength) * 8
+	cipsoEnumLength        = 2
+
+	ripsoTerminationBit = 0x01
+)
+
+// CIPSOTagType
+// 1	Restricted bitmap
+// 2	Enumerated categories
+// 5	Ranged categories
+// 6	Permissive bitmap
+// 7	Free form
+type CIPSOTagType uint8
+
+const (
+	CIPSOTagRestrictedBitmap CIPSOTagType = 1
+	CIPSOTagEnumerated       CIPSOTagType = 2
+	CIPSOTagRanged           CIPSOTagType = 5
+	CIPSOTagPermissiveBitmap CIPSOTagType = 6
+	CIPSOTagFreeForm         CIPSOTagType = 7
+)
+
+func (t CIPSOTagType) String() string {
+	switch t {
+	case CIPSOTagRestrictedBitmap:
+		return "restricted bitmap"
+	case CIPSOTagEnumerated:
+		return "enumerated"
+	case CIPSOTagRanged:
+		return "ranged"
+	case CIPSOTagPermissiveBitmap:
+		return "permissive bitmap"
+	case CIPSOTagFreeForm:
+		return "free form"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(t))
+	}
+}
+
+func (t CIPSOTagType) hasLevel() bool {
+	switch t {
+	case CIPSOTagRestrictedBitmap, CIPSOTagEnumerated, CIPSOTagRanged, CIPSOTagPermissiveBitmap:
+		return true
+	default:
+		return false
+	}
+}
+
+// CIPSOCategoryRange
+// range of categories from Low to High inclusive
+type CIPSOCategoryRange struct {
+	High uint16
+	Low  uint16
+}
+
+// CIPSOTag
+// one tag of CIPSO option
+// Level is set for all tags except free form
+// Categories is set for bitmap (as bit numbers) and enumerated tags
+// Ranges is set for ranged tag
+// Data is set for free form and unknown tags. Data aliases option data
+type CIPSOTag struct {
+	Type       CIPSOTagType
+	Level      uint8
+	Categories []uint16
+	Ranges     []CIPSOCategoryRange
+	Data       []byte
+}
+
+// CIPSOOption
+// typed view of Commercial IP Security Option
+type CIPSOOption struct {
+	// DOI
+	// domain of interpretation
+	DOI  uint32
+	Tags []CIPSOTag
+}
+
+// RIPSOClassification
+// classification level of Basic Security option (RFC 1108)
+type RIPSOClassification uint8
+
+const (
+	RIPSOReserved4    RIPSOClassification = 0x01
+	RIPSOTopSecret    RIPSOClassification = 0x3D
+	RIPSOSecret       RIPSOClassification = 0x5A
+	RIPSOConfidential RIPSOClassification = 0x96
+	RIPSOReserved3    RIPSOClassification = 0x66
+	RIPSOReserved2    RIPSOClassification = 0xCC
+	RIPSOUnclassified RIPSOClassification = 0xAB
+	RIPSOReserved1    RIPSOClassification = 0xF1
+)
+
+var ripsoClassificationsMap = map[RIPSOClassification]string{
+	RIPSOReserved4:    "Reserved 4",
+	RIPSOTopSecret:    "Top Secret",
+	RIPSOSecret:       "Secret",
+	RIPSOConfidential: "Confidential",
+	RIPSOReserved3:    "Reserved 3",
+	RIPSOReserved2:    "Reserved 2",
+	RIPSOUnclassified: "Unclassified",
+	RIPSOReserved1:    "Reserved 1",
+}
+
+func (c RIPSOClassification) IsValid() bool {
+	_, ok := ripsoClassificationsMap[c]
+	return ok
+}
+
+func (c RIPSOClassification) String() string {
+	if name, ok := ripsoClassificationsMap[c]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("Unknown(0x%02x)", uint8(c))
+}
+
+// ProtectionAuthority
+// one octet of protection authority flags of Basic Security option
+// Field termination bit is not included
+type ProtectionAuthority uint8
+
+const (
+	ProtectionAuthorityGENSER  ProtectionAuthority = 0x80
+	ProtectionAuthoritySIOPESI ProtectionAuthority = 0x40
+	ProtectionAuthoritySCI     ProtectionAuthority = 0x20
+	ProtectionAuthorityNSA     ProtectionAuthority = 0x10
+	ProtectionAuthorityDOE     ProtectionAuthority = 0x08
+)
+
+var protectionAuthoritiesNames = []struct {
+	flag ProtectionAuthority
+	name string
+}{
+	{flag: ProtectionAuthorityGENSER, name: "GENSER"},
+	{flag: ProtectionAuthoritySIOPESI, name: "SIOP-ESI"},
+	{flag: ProtectionAuthoritySCI, name: "SCI"},
+	{flag: ProtectionAuthorityNSA, name: "NSA"},
+	{flag: ProtectionAuthorityDOE, name: "DOE"},
+}
+
+func (a ProtectionAuthority) Has(flag ProtectionAuthority) bool {
+	return a&flag == flag
+}
+
+// String
+// returns names of known flags separated by space for first octet
+func (a ProtectionAuthority) String() string {
+	names := make([]string, 0, len(protectionAuthoritiesNames))
+
+	for _, n := range protectionAuthoritiesNames {
+		if a.Has(n.flag) {
+			names = append(names, n.name)
+		}
+	}
+
+	unknown := a &^ (ProtectionAuthorityGENSER | ProtectionAuthoritySIOPESI | ProtectionAuthoritySCI |
+		ProtectionAuthorityNSA | ProtectionAuthorityDOE | ripsoTerminationBit)
+	if unknown != 0 {
+		names = append(names, fmt.Sprintf("0x%02x", uint8(unknown)))
+	}
+
+	if len(names) == 0 {
+		return "none"
+	}
+
+	return strings.Join(names, " ")
+}
+
+// RIPSOOption
+// typed view of Basic Security option (RFC 1108)
+type RIPSOOption struct {
+	Classification RIPSOClassification
+	// Authorities
+	// protection authority octets without field termination bit
+	// Flags constants are applicable to first octet only
+	Authorities []ProtectionAuthority
+}
+
+// ExtendedSecurityOption
+// typed view of Extended Security option (RFC 1108)
+type ExtendedSecurityOption struct {
+	FormatCode uint8
+	// Info
+	// additional security info. Info aliases option data
+	Info []byte
+}
+
+// CIPSO
+// returns typed view of Commercial IP Security Option
+// returns ErrWrongOptionType error for other options
+func (o *Option) CIPSO() (*CIPSOOption, error) {
+	if err := o.assertType(OptionCommercialIPSecurityOption); err != nil {
+		return nil, err
+	}
+
+	if len(o.data) < cipsoDOILength {
+		return nil, o.invalidFieldErr("length", 1, fmt.Sprintf(">= %d", 2+cipsoDOILength), o.GetLength())
+	}
+
+	res := &CIPSOOption{
+		DOI: binary.BigEndian.Uint32(o.data[:cipsoDOILength]),
+	}
+
+	// tagOffset
+	// offset of tag from option start
+	tagOffset := 2 + cipsoDOILength
+
+	for data := o.data[cipsoDOILength:]; len(data) > 0; {
+		if len(data) < cipsoTagHeaderLength {
+			return nil, o.invalidFieldErr(
+				fmt.Sprintf("tag %d header length", data[0]),
+				tagOffset,
+				fmt.Sprintf(">= %d", cipsoTagHeaderLength),
+				len(data),
+			)
+		}
+
+		tagLen := int(data[1])
+		if tagLen < cipsoTagHeaderLength || tagLen > len(data) {
+			return nil, o.invalidFieldErr(
+				fmt.Sprintf("tag %d length", data[0]),
+				tagOffset+1,
+				fmt.Sprintf("from %d to remaining option length %d", cipsoTagHeaderLength, len(data)),
+				tagLen,
+			)
+		}
+
+		tag, err := o.parseCIPSOTag(data[:tagLen], tagOffset)
+		if err != nil {
+			return nil, err
+		}
+
+		res.Tags = append(res.Tags, tag)
+		data = data[tagLen:]
+		tagOffset += tagLen
+	}
+
+	return res, nil
+}
+
+func (o *Option) parseCIPSOTag(data []byte, tagOffset int) (CIPSOTag, error) {
+	tag := CIPSOTag{Type: CIPSOTagType(data[0])}
+
+	if !tag.Type.hasLevel() {
+		tag.Data = data[cipsoTagHeaderLength:]
+		return tag, nil
+	}
+
+	if len(data) < cipsoLevelTagMinLength || len(data) > cipsoMaxTagLength {
+		return CIPSOTag{}, o.invalidFieldErr(
+			fmt.Sprintf("tag %d length", tag.Type),
+			tagOffset+1,
+			fmt.Sprintf("from %d to %d", cipsoLevelTagMinLength, cipsoMaxTagLength),
+			len(data),
+		)
+	}
+
+	tag.Level = data[3]
+	body := data[cipsoLevelTagMinLength:]
+
+	switch tag.Type {
+	case CIPSOTagRestrictedBitmap, CIPSOTagPermissiveBitmap:
+		for i, octet := range body {
+			for bit := 0; bit < 8; bit++ {
+				if octet&(0x80>>bit) != 0 {
+					tag.Categories = append(tag.Categories, uint16(i*8+bit))
+				}
+			}
+		}
+	case CIPSOTagEnumerated:
+		if len(body)%cipsoEnumLength != 0 {
+			return CIPSOTag{}, o.invalidTagBodyLengthErr(tag.Type, tagOffset, len(data))
+		}
+
+		for d := body; len(d) >= cipsoEnumLength; d = d[cipsoEnumLength:] {
+			tag.Categories = append(tag.Categories, binary.BigEndian.Uint16(d))
+		}
+	case CIPSOTagRanged:
+		if len(body)%cipsoEnumLength != 0 {
+			return CIPSOTag{}, o.invalidTagBodyLengthErr(tag.Type, tagOffset, len(data))
+		}
+
+		// low category of last range can be omitted, it means 0
+		for d := body; len(d) >= cipsoEnumLength; {
+			r := CIPSOCategoryRange{High: binary.BigEndian.Uint16(d)}
+			d = d[cipsoEnumLength:]
+
+			if len(d) >= cipsoEnumLength {
+				r.Low = binary.BigEndian.Uint16(d)
+				d = d[cipsoEnumLength:]
+			}
+
+			tag.Ranges = append(tag.Ranges, r)
+		}
+	}
+
+	return tag, nil
+}
+
+// invalidTagBodyLengthErr
+// returns ParseError for enumerated or ranged tag with categories not aligned to 2 bytes
+func (o *Option) invalidTagBodyLengthErr(tagType CIPSOTagType, tagOffset int, tagLen int) error {
+	return o.invalidFieldErr(
+		fmt.Sprintf("tag %d length", tagType),
+		tagOffset+1,
+		fmt.Sprintf("%d + %d*n", cipsoLevelTagMinLength, cipsoEnumLength),
+		tagLen,
+	)
+}
+
+// RIPSO
+// returns typed view of Basic Security option
+// returns ErrWrongOptionType error for other options
+func (o *Option) RIPSO() (*RIPSOOption, error) {
+	if err := o.assertType(OptionSecurityRIPSO); err != nil {
+		return nil, err
+	}
+
+	if len(o.data) < 1 {
+		return nil, o.invalidFieldErr("length", 1, ">= 3", o.GetLength())
+	}
+
+	res := &RIPSOOption{
+		Classification: RIPSOClassification(o.data[0]),
+	}
+
+	if !res.Classification.IsValid() {
+		return nil, o.invalidFieldErr("classification level", 2, "known classification level", int(o.data[0]))
+	}
+
+	authorities := o.data[1:]
+	for i, octet := range authorities {
+		last := i == len(authorities)-1
+		hasMore := octet&ripsoTerminationBit != 0
+
+		if last == hasMore {
+			expected := "octet with termination bit"
+			if last {
+				expected = "last octet without termination bit"
+			}
+
+			return nil, o.invalidFieldErr("protection authority", 3+i, expected, int(octet))
+		}
+
+		res.Authorities = append(res.Authorities, ProtectionAuthority(octet&^ripsoTerminationBit))
+	}
+
+	return res, nil
+}
+
+// ExtendedSecurity
+// returns typed view of Extended Security option
+// returns ErrWrongOptionType error for other options
+func (o *Option) ExtendedSecurity() (*ExtendedSecurityOption, error) {
+	if err := o.assertType(OptionExtendedSecurityRIPSO); err != nil {
+		return nil, err
+	}
+
+	if len(o.data) < 1 {
+		return nil, o.invalidFieldErr("length", 1, ">= 3", o.GetLength())
+	}
+
+	return &ExtendedSecurityOption{
+		FormatCode: o.data[0],
+		Info:       o.data[1:],
+	}, nil
+}
+
+// NewCIPSOOption
+// encodes Commercial IP Security Option
+// Bitmap categories should be less than 240, tag data should fit into 34 bytes
+func NewCIPSOOption(cipso CIPSOOption) (Option, error) {
+	data := binary.BigEndian.AppendUint32(nil, cipso.DOI)
+
+	for _, tag := range cipso.Tags {
+		var err error
+
+		data, err = appendCIPSOTag(data, tag)
+		if err != nil {
+			opt := Option{typeID: uint8(OptionCommercialIPSecurityOption)}
+			return Option{}, opt.wrapError("%w", err)
+		}
+	}
+
+	return NewOption(OptionCommercialIPSecurityOption, data)
+}
+
+func appendCIPSOTag(data []byte, tag CIPSOTag) ([]byte, error) {
+	start := len(data)
+
+	data = append(data, uint8(tag.Type), 0)
+
+	if !tag.Type.hasLevel() {
+		data = append(data, tag.Data...)
+		return fixCIPSOTagLength(data, start)
+	}
+
+	data = append(data, 0, tag.Level)
+
+	switch tag.Type {
+	case CIPSOTagRestrictedBitmap, CIPSOTagPermissiveBitmap:
+		if len(tag.Categories) > 0 {
+			maxCategory := slices.Max(tag.Categories)
+			if maxCategory >= cipsoMaxCategory {
+				return nil, fmt.Errorf("tag %d category %d exceeds %d", tag.Type, maxCategory, cipsoMaxCategory-1)
+			}
+
+			bitmap := make([]byte, maxCategory/8+1)
+			for _, category := range tag.Categories {
+				bitmap[category/8] |= 0x80 >> (category % 8)
+			}
+
+			data = append(data, bitmap...)
+		}
+	case CIPSOTagEnumerated:
+		for _, category := range tag.Categories {
+			data = binary.BigEndian.AppendUint16(data, category)
+		}
+	case CIPSOTagRanged:
+		for _, r := range tag.Ranges {
+			data = binary.BigEndian.AppendUint16(data, r.High)
+			data = binary.BigEndian.AppendUint16(data, r.Low)
+		}
+	}
+
+	return fixCIPSOTagLength(data, start)
+}
+
+func fixCIPSOTagLength(data []byte, start int) ([]byte, error) {
+	tagLen := len(data) - start
+	if tagLen > cipsoMaxTagLength {
+		return nil, fmt.Errorf("tag %d length %d exceeds %d", data[start], tagLen, cipsoMaxTagLength)
+	}
+
+	data[start+1] = uint8(tagLen)
+
+	return data, nil
+}
+
+// NewRIPSOOption
+// encodes Basic Security option
+// Field termination bit is set for all authorities octets except last
+func NewRIPSOOption(ripso RIPSOOption) (Option, error) {
+	if !ripso.Classification.IsValid() {
+		opt := Option{typeID: uint8(OptionSecurityRIPSO)}
+		return Option{}, opt.wrapError("invalid classification level 0x%02x", uint8(ripso.Classification))
+	}
+
+	data := make([]byte, 0, 1+len(ripso.Authorities))
+	data = append(data, uint8(ripso.Classification))
+
+	for i, authority := range ripso.Authorities {
+		octet := uint8(authority) &^ ripsoTerminationBit
+		if i < len(ripso.Authorities)-1 {
+			octet |= ripsoTerminationBit
+		}
+
+		data = append(data, octet)
+	}
+
+	return NewOption(OptionSecurityRIPSO, data)
+}
+
+// NewExtendedSecurityOption
+// encodes Extended Security option
+func NewExtendedSecurityOption(security ExtendedSecurityOption) (Option, error) {
+	data := make([]byte, 0, 1+len(security.Info))
+	data = append(data, security.FormatCode)
+	data = append(data, security.Info...)
+
+	return NewOption(OptionExtendedSecurityRIPSO, data)
+}
+
+func (o *Option) writeCIPSO(b *strings.Builder) bool {
+	cipso, err := o.CIPSO()
+	if err != nil {
+		return false
+	}
+
+	b.WriteString(stringsutils.FmtLnWithTabPrefix("DOI: %d", cipso.DOI))
+
+	if len(cipso.Tags) == 0 {
+		b.WriteString(stringsutils.FmtWithTabPrefix("No tags"))
+		return true
+	}
+
+	tagsStrings := make([]string, 0, len(cipso.Tags))
+	for _, tag := range cipso.Tags {
+		tagsStrings = append(tagsStrings, cipsoTagString(tag))
+	}
+
+	b.WriteString(stringsutils.FmtLnWithTabPrefix("Tags:"))
+	b.WriteString(stringsutils.ShiftOnTabs(strings.Join(tagsStrings, "\n"), 2))
+
+	return true
+}
+
+func cipsoTagString(tag CIPSOTag) string {
+	b := strings.Builder{}
+
+	b.WriteString(stringsutils.FmtLn("Tag: %s(%d)", tag.Type.String(), tag.Type))
+
+	if !tag.Type.hasLevel() {
+		if len(tag.Data) == 0 {
+			b.WriteString(stringsutils.FmtWithTabPrefix("No data"))
+			return b.String()
+		}
+
+		b.WriteString(stringsutils.FmtLnWithTabPrefix("Hex data:"))
+		b.WriteString(stringsutils.ShiftOnTabs(stringsutils.BytesToHexWithWrap(tag.Data, 8), 2))
+
+		return b.String()
+	}
+
+	b.WriteString(stringsutils.FmtLnWithTabPrefix("Level: %d", tag.Level))
+
+	if tag.Type == CIPSOTagRanged {
+		rangesStrings := make([]string, 0, len(tag.Ranges))
+		for _, r := range tag.Ranges {
+			rangesStrings = append(rangesStrings, fmt.Sprintf("%d-%d", r.Low, r.High))
+		}
+
+		b.WriteString(stringsutils.FmtWithTabPrefix("Ranges: %s", strings.Join(rangesStrings, " ")))
+
+		return b.String()
+	}
+
+	categoriesStrings := make([]string, 0, len(tag.Categories))
+	for _, category := range tag.Categories {
+		categoriesStrings = append(categoriesStrings, fmt.Sprintf("%d", category))
+	}
+
+	b.WriteString(stringsutils.FmtWithTabPrefix("Categories: %s", strings.Join(categoriesStrings, " ")))
+
+	return b.String()
+}
+
+func (o *Option) writeRIPSO(b *strings.Builder) bool {
+	ripso, err := o.RIPSO()
+	if err != nil {
+		return false
+	}
+
+	b.WriteString(stringsutils.FmtLnWithTabPrefix(
+		"Classification: %s(0x%02x)",
+		ripso.Classification.String(),
+		uint8(ripso.Classification),
+	))
+
+	if len(ripso.Authorities) == 0 {
+		b.WriteString(stringsutils.FmtWithTabPrefix("No protection authorities"))
+		return true
+	}
+
+	authoritiesStrings := make([]string, 0, len(ripso.Authorities))
+	authoritiesStrings = append(authoritiesStrings, ripso.Authorities[0].String())
+
+	for _, authority := range ripso.Authorities[1:] {
+		authoritiesStrings = append(authoritiesStrings, fmt.Sprintf("0x%02x", uint8(authority)))
+	}
+
+	b.WriteString(stringsutils.FmtWithTabPrefix("Protection authorities: %s", strings.Join(authoritiesStrings, " ")))
+
+	return true
+}
+
+func (o *Option) writeExtendedSecurity(b *strings.Builder) bool {
+	security, err := o.ExtendedSecurity()
+	if err != nil {
+		return false
+	}
+
+	b.WriteString(stringsutils.FmtLnWithTabPrefix("Format code: %d", security.FormatCode))
+
+	if len(security.Info) == 0 {
+		b.WriteString(stringsutils.FmtWithTabPrefix("No additional security info"))
+		return true
+	}
+
+	b.WriteString(stringsutils.FmtLnWithTabPrefix("Additional security info:"))
+	b.WriteString(stringsutils.ShiftOnTabs(stringsutils.BytesToHexWithWrap(security.Info, 8), 2))
+
+	return true
+}
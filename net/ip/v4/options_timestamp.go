@@ -0,0 +1,249 @@
+// Copyright 2026
+// license that can be found in the LICENSE file.
+
+package v4
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+
+	stringsutils "github.com/name212/netpacket/utils/strings"
+)
+
+const (
+	timestampLength = 4
+	// timestampMinPointer
+	// pointer is counted from option type byte, first entry starts after type, length, pointer and flags
+	timestampMinPointer = 5
+	// timestampNonStandardBit
+	// set if timestamp is not milliseconds from midnight UT
+	timestampNonStandardBit = 0x80000000
+)
+
+// TimestampFlag
+// 0	timestamps only
+// 1	each timestamp is preceded with internet address of the registering entity
+// 3	internet address fields are prespecified
+type TimestampFlag uint8
+
+const (
+	TimestampOnly         TimestampFlag = 0
+	TimestampWithAddress  TimestampFlag = 1
+	TimestampPrespecified TimestampFlag = 3
+)
+
+func (f TimestampFlag) String() string {
+	switch f {
+	case TimestampOnly:
+		return "timestamps only"
+	case TimestampWithAddress:
+		return "address and timestamp"
+	case TimestampPrespecified:
+		return "prespecified addresses"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(f))
+	}
+}
+
+func (f TimestampFlag) hasAddress() bool {
+	return f == TimestampWithAddress || f == TimestampPrespecified
+}
+
+func (f TimestampFlag) entryLength() int {
+	if f.hasAddress() {
+		return net.IPv4len + timestampLength
+	}
+
+	return timestampLength
+}
+
+// TimestampEntry
+// one slot of Timestamp option
+// Address is nil for TimestampOnly flag. Address aliases option data
+type TimestampEntry struct {
+	Address   net.IP
+	Timestamp uint32
+}
+
+// IsStandard
+// returns true if timestamp is milliseconds from midnight UT
+func (e *TimestampEntry) IsStandard() bool {
+	return e.Timestamp&timestampNonStandardBit == 0
+}
+
+// TimestampOption
+// typed view of Timestamp option
+type TimestampOption struct {
+	// Pointer
+	// raw pointer field. Points to octet (counted from 1 from option type) of next entry slot
+	Pointer uint8
+	// Overflow
+	// count of modules which cannot register timestamp due to lack of space
+	Overflow uint8
+	Flag     TimestampFlag
+	// Entries
+	// all entry slots including not recorded. See NextIndex
+	Entries []TimestampEntry
+}
+
+// NextIndex
+// returns index in Entries of next entry slot
+// returns -1 if option is full (pointer greater than option length)
+func (t *TimestampOption) NextIndex() int {
+	index := (int(t.Pointer) - timestampMinPointer) / t.Flag.entryLength()
+	if index >= len(t.Entries) {
+		return -1
+	}
+
+	return index
+}
+
+// IsFull
+// returns true if all entry slots are used
+func (t *TimestampOption) IsFull() bool {
+	return t.NextIndex() < 0
+}
+
+// Recorded
+// returns entries which were recorded
+func (t *TimestampOption) Recorded() []TimestampEntry {
+	next := t.NextIndex()
+	if next < 0 {
+		return t.Entries
+	}
+
+	return t.Entries[:next]
+}
+
+// Timestamp
+// returns typed view of Timestamp option
+// returns ErrWrongOptionType error for other options
+// Supports 0, 1 and 3 flags, validates pointer and length consistency
+func (o *Option) Timestamp() (*TimestampOption, error) {
+	if err := o.assertType(OptionTimeStamp); err != nil {
+		return nil, err
+	}
+
+	if len(o.data) < 2 {
+		return nil, o.invalidFieldErr("length", 1, ">= 4", o.GetLength())
+	}
+
+	res := &TimestampOption{
+		Pointer:  o.data[0],
+		Overflow: o.data[1] >> 4,
+		Flag:     TimestampFlag(o.data[1] & 0x0F),
+	}
+
+	switch res.Flag {
+	case TimestampOnly, TimestampWithAddress, TimestampPrespecified:
+	default:
+		return nil, o.invalidFieldErr("flag", 3, "0, 1 or 3", int(res.Flag))
+	}
+
+	entryLength := res.Flag.entryLength()
+
+	entriesData := o.data[2:]
+	if len(entriesData)%entryLength != 0 {
+		return nil, o.invalidFieldErr("length", 1, fmt.Sprintf("4 + %d*n for flag %d", entryLength, res.Flag), o.GetLength())
+	}
+
+	if res.Pointer < timestampMinPointer {
+		return nil, o.invalidFieldErr("pointer", 2, fmt.Sprintf(">= %d", timestampMinPointer), int(res.Pointer))
+	}
+
+	if (int(res.Pointer)-timestampMinPointer)%entryLength != 0 {
+		return nil, o.invalidFieldErr("pointer", 2, fmt.Sprintf("%d + %d*n", timestampMinPointer, entryLength), int(res.Pointer))
+	}
+
+	if int(res.Pointer) > o.GetLength()+1 {
+		return nil, o.invalidFieldErr("pointer", 2, fmt.Sprintf("<= %d", o.GetLength()+1), int(res.Pointer))
+	}
+
+	res.Entries = make([]TimestampEntry, 0, len(entriesData)/entryLength)
+	for d := entriesData; len(d) >= entryLength; d = d[entryLength:] {
+		entry := TimestampEntry{}
+		if res.Flag.hasAddress() {
+			entry.Address = net.IP(d[:net.IPv4len])
+		}
+
+		entry.Timestamp = binary.BigEndian.Uint32(d[entryLength-timestampLength : entryLength])
+		res.Entries = append(res.Entries, entry)
+	}
+
+	return res, nil
+}
+
+func (o *Option) writeTimestamp(b *strings.Builder) bool {
+	ts, err := o.Timestamp()
+	if err != nil {
+		return false
+	}
+
+	b.WriteString(stringsutils.FmtLnWithTabPrefix("Pointer: %d", ts.Pointer))
+	b.WriteString(stringsutils.FmtLnWithTabPrefix("Overflow: %d", ts.Overflow))
+	b.WriteString(stringsutils.FmtLnWithTabPrefix("Flag: %s(%d)", ts.Flag.String(), ts.Flag))
+
+	recorded := ts.Recorded()
+	if len(recorded) == 0 {
+		b.WriteString(stringsutils.FmtWithTabPrefix("No timestamps recorded"))
+		return true
+	}
+
+	entriesStrings := make([]string, 0, len(recorded))
+	for i, entry := range recorded {
+		entryStr := fmt.Sprintf("%d: %d", i, entry.Timestamp)
+		if entry.Address != nil {
+			entryStr = fmt.Sprintf("%d: %s %d", i, entry.Address.String(), entry.Timestamp)
+		}
+
+		if !entry.IsStandard() {
+			entryStr += " (non-standard)"
+		}
+
+		entriesStrings = append(entriesStrings, entryStr)
+	}
+
+	b.WriteString(stringsutils.FmtLnWithTabPrefix("Timestamps:"))
+	b.WriteString(stringsutils.ShiftOnTabs(strings.Join(entriesStrings, "\n"), 2))
+
+	return true
+}
+
+// NewTimestampOption
+// creates Timestamp option with all entries from ts
+// Zero pointer is replaced with pointer to first entry. Nil addresses are encoded as 0.0.0.0
+// Addresses are ignored for TimestampOnly flag
+func NewTimestampOption(ts TimestampOption) (Option, error) {
+	opt := Option{typeID: uint8(OptionTimeStamp)}
+
+	if ts.Overflow > 0x0F {
+		return Option{}, opt.wrapError("overflow %d exceeds %d", ts.Overflow, 0x0F)
+	}
+
+	pointer := ts.Pointer
+	if pointer == 0 {
+		pointer = timestampMinPointer
+	}
+
+	data := make([]byte, 0, 2+len(ts.Entries)*ts.Flag.entryLength())
+	data = append(data, pointer, ts.Overflow<<4|uint8(ts.Flag)&0x0F)
+
+	for _, entry := range ts.Entries {
+		if ts.Flag.hasAddress() {
+			switch {
+			case entry.Address == nil:
+				data = append(data, 0, 0, 0, 0)
+			case entry.Address.To4() == nil:
+				return Option{}, opt.wrapError("address %s is not IPv4 address", entry.Address)
+			default:
+				data = append(data, entry.Address.To4()...)
+			}
+		}
+
+		data = binary.BigEndian.AppendUint32(data, entry.Timestamp)
+	}
+
+	return NewOption(OptionTimeStamp, data)
+}
@@ -4,12 +4,15 @@
 package v4
 
 import (
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"net"
+	"slices"
 	"strings"
 
 	"github.com/name212/netpacket"
+	"github.com/name212/netpacket/checksum"
 	"github.com/name212/netpacket/transport/tcp"
 	"github.com/name212/netpacket/transport/udp"
 	stringsutils "github.com/name212/netpacket/utils/strings"
@@ -17,11 +20,20 @@ import (
 
 var ErrNotTransportPacket = errors.New("not transport packet")
 
+// Transport
+// UDP datagram or TCP packet carried by IPv4 packet
 type Transport interface {
+	netpacket.Layer
+
 	GetSourcePort() int
 	GetDestinationPort() int
 	GetPayload() []byte
-	Kind() netpacket.Kind
+}
+
+// checksumVerifier
+// transport which checksum can be verified with IPv4 pseudo-header
+type checksumVerifier interface {
+	VerifyChecksum(source, destination net.IP) checksum.Status
 }
 
 type Packet struct {
@@ -29,28 +41,127 @@ type Packet struct {
 
 	headerData []byte
 	payload    []byte
+	trailer    []byte
+
+	anomalies netpacket.Anomalies
+	truncated bool
+}
+
+// NewPacket
+// creates packet from header and payload for serialization
+// Header data is empty for created packet
+func NewPacket(header *Header, payload []byte) *Packet {
+	return &Packet{
+		header:  header,
+		payload: payload,
+	}
 }
 
 // ParsePacket parses the IPv4 header and extract payload also save header data
+// Payload is limited by total length from header, bytes beyond it
+// (for example Ethernet padding) are returned by GetTrailer
 // ParsePacket save slices from data. You should copy data before parse
 // to avoid hold full data in memory
 func ParsePacket(data []byte) (*Packet, error) {
-	header, err := ParseHeader(data)
-	if err != nil {
-		return nil, netpacket.WrapCannotParseHeaderErr(err)
+	return ParsePacketWithOptions(data, netpacket.ParseOptions{})
+}
+
+// ParsePacketWithOptions
+// same as ParsePacket but applies additional parsing options
+// With opts.VerifyChecksum returns ErrBadChecksum error if header checksum is invalid,
+// not computed (zero) checksum is not rejected. See ChecksumStatus
+// With opts.Copy data is copied before parsing, so packet does not alias data
+// With lenient opts.Policy packet with violations which do not prevent parsing
+// is not rejected and violations are reported by Anomalies
+// With opts.AllowTruncated packet shorter than total length is not rejected,
+// available payload is returned and packet is marked as truncated
+func ParsePacketWithOptions(data []byte, opts netpacket.ParseOptions) (*Packet, error) {
+	packet := &Packet{}
+
+	if err := ParsePacketInto(data, opts, packet); err != nil {
+		return nil, err
+	}
+
+	return packet, nil
+}
+
+// ParsePacketInto
+// same as ParsePacketWithOptions but fills passed packet
+// Header of packet is reused if it is set, so parsing to reused packet
+// does not produce heap allocations. On error packet content is undefined
+func ParsePacketInto(data []byte, opts netpacket.ParseOptions, packet *Packet) error {
+	data = opts.PrepareData(data)
+
+	header := packet.header
+	if header == nil {
+		header = &Header{}
+	}
+
+	packet.anomalies = packet.anomalies[:0]
+
+	if err := parseHeaderInto(data, opts, header, &packet.anomalies); err != nil {
+		return netpacket.WrapParseErr(err)
+	}
+
+	if opts.VerifyChecksum {
+		if err := header.verifyDataChecksum(data); err != nil {
+			return err
+		}
 	}
 
 	totalLen := header.GetTotalLen()
+	truncated := false
 
 	if totalLen > len(data) {
-		return nil, fmt.Errorf("data too short to contain an IPv4 all packet header len %d data len %d", totalLen, len(data))
+		err := netpacket.NewShortDataErr(Kind, "total length", 2, fmt.Sprintf("<= data length %d", len(data)), totalLen)
+		if !opts.AllowTruncated {
+			return err
+		}
+
+		truncated = true
+		packet.anomalies.Add(netpacket.Anomaly{
+			Code:    netpacket.AnomalyLengthExceedsData,
+			Layer:   Kind,
+			Offset:  err.Offset,
+			Message: fmt.Sprintf("IP total length %d exceeds data length %d", totalLen, len(data)),
+		})
 	}
 
-	return &Packet{
+	if totalLen < len(data) {
+		packet.anomalies.Add(netpacket.Anomaly{
+			Code:    netpacket.AnomalyTrailingData,
+			Layer:   Kind,
+			Offset:  totalLen,
+			Message: fmt.Sprintf("%d bytes beyond IP total length %d", len(data)-totalLen, totalLen),
+		})
+	}
+
+	headerLengthBytes := min(header.HeaderLen(), len(data))
+	packetEnd := max(min(totalLen, len(data)), headerLengthBytes)
+
+	*packet = Packet{
 		header:     header,
-		headerData: data[:header.HeaderLen()],
-		payload:    extractPayload(data, header.HeaderLen()),
-	}, nil
+		headerData: data[:headerLengthBytes],
+		payload:    extractPayload(data[:packetEnd], headerLengthBytes),
+		trailer:    extractPayload(data, packetEnd),
+		anomalies:  packet.anomalies,
+		truncated:  truncated,
+	}
+
+	return nil
+}
+
+// Clone
+// returns deep copy of packet which does not alias parsed data
+func (p *Packet) Clone() *Packet {
+	return &Packet{
+		header:     p.header.Clone(),
+		headerData: slices.Clone(p.headerData),
+		payload:    slices.Clone(p.payload),
+		trailer:    slices.Clone(p.trailer),
+		anomalies:  slices.Clone(p.anomalies),
+		truncated:  p.truncated,
+	}
 }
 
 func (p *Packet) GetSourceIP() net.IP {
@@ -89,10 +200,68 @@ func (p *Packet) GetHeaderData() []byte {
 	return p.headerData
 }
 
+// GetTrailer
+// returns bytes beyond total length from header, for example Ethernet padding
+// Trailer is not serialized with packet
+func (p *Packet) GetTrailer() []byte {
+	return p.trailer
+}
+
+// IsTruncated
+// returns true if packet was parsed with AllowTruncated option
+// and data is shorter than total length from header
+func (p *Packet) IsTruncated() bool {
+	return p.truncated
+}
+
+// Anomalies
+// returns protocol violations found during parsing
+// Empty for valid packets and for packets which were not parsed
+func (p *Packet) Anomalies() netpacket.Anomalies {
+	return p.anomalies
+}
+
 func (p *Packet) Kind() netpacket.Kind {
 	return Kind
 }
 
+// Contents
+// returns header data. See GetHeaderData
+func (p *Packet) Contents() []byte {
+	return p.headerData
+}
+
+// Payload
+// returns payload. See GetPayload
+func (p *Packet) Payload() []byte {
+	return p.payload
+}
+
+// NextLayerKind
+// returns transport kind for UDP and TCP packets
+// Payload of any fragment does not contain full transport packet
+// (not first fragment does not contain even transport header),
+// netpacket.KindPayload returns for fragments and for other protocols
+// Use Defragmenter to get transport layer of fragmented packet
+func (p *Packet) NextLayerKind() netpacket.Kind {
+	if len(p.payload) == 0 {
+		return ""
+	}
+
+	if isFragment(p.GetHeader()) {
+		return netpacket.KindPayload
+	}
+
+	switch p.GetProtocol() {
+	case ProtocolTCP:
+		return tcp.Kind
+	case ProtocolUDP:
+		return udp.Kind
+	default:
+		return netpacket.KindPayload
+	}
+}
+
 func (p *Packet) IsTransport() bool {
 	proto := p.GetHeader().GetProtocol()
 	return proto == ProtocolTCP || proto == ProtocolUDP
@@ -100,7 +269,16 @@ func (p *Packet) IsTransport() bool {
 
 // TransportPacket
 // returns ErrNotTransportPacket error if packet is not UDP or TCP
+// Transport of truncated packet is parsed with AllowTruncated option. See IsTruncated
 func (p *Packet) TransportPacket() (Transport, error) {
+	return p.transportPacket(netpacket.ParseOptions{AllowTruncated: p.truncated})
+}
+
+// transportPacket
+// parses transport packet with opts
+// With opts.VerifyChecksum checksum is verified with IPv4 pseudo-header and
+// its status is saved in transport. Transport with invalid checksum is not rejected
+func (p *Packet) transportPacket(opts netpacket.ParseOptions) (Transport, error) {
 	payload := p.GetPayload()
 	if len(payload) == 0 {
 		return nil, netpacket.WrapShortDataErr(netpacket.ErrEmptyPayload)
@@ -108,23 +286,69 @@ func (p *Packet) TransportPacket() (Transport, error) {
 
 	header := p.GetHeader()
 
-	var inner Transport
-	var err error
+	var transport Transport
 
 	switch header.GetProtocol() {
 	case ProtocolTCP:
-		inner, err = tcp.ParsePacket(payload)
+		inner, err := tcp.ParsePacketWithOptions(payload, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		transport = inner
 	case ProtocolUDP:
-		inner, err = udp.ParseDatagram(payload)
+		inner, err := udp.ParseDatagramWithOptions(payload, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		transport = inner
 	default:
 		return nil, fmt.Errorf("%w %s", ErrNotTransportPacket, header.ProtocolString())
 	}
 
+	if opts.VerifyChecksum {
+		p.verifyTransportChecksum(transport)
+	}
+
+	return transport, nil
+}
+
+// ComputeChecksum
+// calculates header checksum over header data for parsed packet
+// and over encoded header fields for created packet. See Header.ComputeChecksum
+// Header data is not changed by changing header fields
+func (p *Packet) ComputeChecksum() uint16 {
+	return p.GetHeader().computeDataChecksum(p.headerData)
+}
+
+// ChecksumStatus
+// verifies header Checksum field with checksum calculated by ComputeChecksum
+// See Header.ChecksumStatus for statuses description
+func (p *Packet) ChecksumStatus() checksum.Status {
+	return headerChecksumStatus(p.GetHeader().Checksum, p.ComputeChecksum())
+}
+
+// VerifyTransportChecksum
+// parses transport packet and verifies its checksum with IPv4 pseudo-header
+// Returned transport keeps checksum status, see udp.Datagram.ChecksumStatus and tcp.Packet.ChecksumStatus
+// returns ErrNotTransportPacket error if packet is not UDP or TCP
+func (p *Packet) VerifyTransportChecksum() (Transport, checksum.Status, error) {
+	transport, err := p.TransportPacket()
 	if err != nil {
-		return nil, err
+		return nil, checksum.StatusNotVerified, err
+	}
+
+	return transport, p.verifyTransportChecksum(transport), nil
+}
+
+func (p *Packet) verifyTransportChecksum(transport Transport) checksum.Status {
+	verifier, ok := transport.(checksumVerifier)
+	if !ok {
+		return checksum.StatusNotVerified
 	}
 
-	return inner, nil
+	return verifier.VerifyChecksum(p.GetSourceIP(), p.GetDestinationIP())
 }
 
 func (p *Packet) String() string {
@@ -134,26 +358,72 @@ func (p *Packet) String() string {
 	b.WriteString(stringsutils.FmtLnWithTabPrefix("Header:"))
 	b.WriteString(stringsutils.ShiftOnTabs(stringsutils.FmtLn(p.GetHeader().String()), 2))
 	b.WriteString(stringsutils.FmtLnWithTabPrefix("Is transport: %v", p.IsTransport()))
+	if anomalies := p.Anomalies(); len(anomalies) > 0 {
+		b.WriteString(stringsutils.FmtLnWithTabPrefix("Anomalies:"))
+		b.WriteString(stringsutils.ShiftOnTabs(stringsutils.FmtLn(anomalies.String()), 2))
+	}
 	b.WriteString(stringsutils.FmtWithTabPrefix("Payload len: %d", len(p.GetPayload())))
 
 	return b.String()
 }
 
+// SerializeTo
+// prepends payload and header to buffer. See Header.SerializeTo
+func (p *Packet) SerializeTo(b *netpacket.SerializeBuffer, opts netpacket.SerializeOptions) error {
+	if err := netpacket.Payload(p.GetPayload()).SerializeTo(b, opts); err != nil {
+		return err
+	}
+
+	return p.GetHeader().SerializeTo(b, opts)
+}
+
+// AppendTo
+// appends header and payload bytes to buf. See Header.SerializeTo
+func (p *Packet) AppendTo(buf []byte, opts netpacket.SerializeOptions) ([]byte, error) {
+	res, err := p.Serialize(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(buf, res...), nil
+}
+
+// Serialize
+// returns packet bytes. See Header.SerializeTo
+func (p *Packet) Serialize(opts netpacket.SerializeOptions) ([]byte, error) {
+	b := netpacket.NewSerializeBufferExpectedSize(maxHeaderLength, len(p.GetPayload()))
+
+	if err := p.SerializeTo(b, opts); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// MarshalBinary
+// returns packet bytes as is without fixing lengths and checksum
+func (p *Packet) MarshalBinary() ([]byte, error) {
+	return p.Serialize(netpacket.SerializeOptions{})
+}
+
 // ToUDP
-// Warning! no additional checks before convert. Can panic.
-// Please check Transport.Kind before conversion
-func ToUDP(t Transport) *udp.Datagram {
-	return t.(*udp.Datagram)
+// converts transport to UDP datagram
+// Second value is false if transport is not UDP datagram
+func ToUDP(t Transport) (*udp.Datagram, bool) {
+	datagram, ok := t.(*udp.Datagram)
+	return datagram, ok
 }
 
 // ToTCP
-// Warning! no additional checks before convert. Can panic.
-// Please check Transport.Kind before conversion
-func ToTCP(t Transport) *tcp.Packet {
-	return t.(*tcp.Packet)
+// converts transport to TCP packet
+// Second value is false if transport is not TCP packet
+func ToTCP(t Transport) (*tcp.Packet, bool) {
+	packet, ok := t.(*tcp.Packet)
+	return packet, ok
 }
 
 // ExtractPayload extract payload from data without full parsing header
+// Payload is limited by total length from header if data is longer
 // ExtractPayload returns subslice from data. You should copy data before parse
 // to avoid hold full data in memory
 func ExtractPayload(data []byte) ([]byte, error) {
@@ -162,6 +432,11 @@ func ExtractPayload(data []byte) ([]byte, error) {
 	}
 
 	headerLenWords := extractHeaderWordsLen(data)
+	totalLen := int(binary.BigEndian.Uint16(data[2:4]))
+
+	if totalLen < len(data) {
+		data = data[:min(max(totalLen, headerLen(headerLenWords)), len(data))]
+	}
 
 	return extractPayload(data, headerLen(headerLenWords)), nil
 }
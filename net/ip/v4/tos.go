@@ -0,0 +1,131 @@
+// Copyright 2026
+// license that can be found in the LICENSE file.
+
+package v4
+
+import "fmt"
+
+const (
+	dscpShift = 2
+	dscpMask  = 0x3F
+	ecnMask   = 0x03
+)
+
+// DSCP
+// Differentiated Services Code Point, 6 high bits of ToS byte (RFC 2474)
+type DSCP uint8
+
+// CS0-CS7	class selectors (RFC 2474)
+// AF11-AF43	assured forwarding (RFC 2597)
+// EF	expedited forwarding (RFC 3246)
+// VA	voice admit (RFC 5865)
+// LE	lower effort (RFC 8622)
+const (
+	DSCPCS0  DSCP = 0
+	DSCPLE   DSCP = 1
+	DSCPCS1  DSCP = 8
+	DSCPAF11 DSCP = 10
+	DSCPAF12 DSCP = 12
+	DSCPAF13 DSCP = 14
+	DSCPCS2  DSCP = 16
+	DSCPAF21 DSCP = 18
+	DSCPAF22 DSCP = 20
+	DSCPAF23 DSCP = 22
+	DSCPCS3  DSCP = 24
+	DSCPAF31 DSCP = 26
+	DSCPAF32 DSCP = 28
+	DSCPAF33 DSCP = 30
+	DSCPCS4  DSCP = 32
+	DSCPAF41 DSCP = 34
+	DSCPAF42 DSCP = 36
+	DSCPAF43 DSCP = 38
+	DSCPCS5  DSCP = 40
+	DSCPVA   DSCP = 44
+	DSCPEF   DSCP = 46
+	DSCPCS6  DSCP = 48
+	DSCPCS7  DSCP = 56
+)
+
+var dscpNames = map[DSCP]string{
+	DSCPCS0:  "CS0",
+	DSCPLE:   "LE",
+	DSCPCS1:  "CS1",
+	DSCPAF11: "AF11",
+	DSCPAF12: "AF12",
+	DSCPAF13: "AF13",
+	DSCPCS2:  "CS2",
+	DSCPAF21: "AF21",
+	DSCPAF22: "AF22",
+	DSCPAF23: "AF23",
+	DSCPCS3:  "CS3",
+	DSCPAF31: "AF31",
+	DSCPAF32: "AF32",
+	DSCPAF33: "AF33",
+	DSCPCS4:  "CS4",
+	DSCPAF41: "AF41",
+	DSCPAF42: "AF42",
+	DSCPAF43: "AF43",
+	DSCPCS5:  "CS5",
+	DSCPVA:   "VA",
+	DSCPEF:   "EF",
+	DSCPCS6:  "CS6",
+	DSCPCS7:  "CS7",
+}
+
+// String
+// returns code point name or number for unnamed code points
+func (d DSCP) String() string {
+	if name, ok := dscpNames[d]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("%d", uint8(d))
+}
+
+// ECN
+// Explicit Congestion Notification, 2 low bits of ToS byte (RFC 3168)
+type ECN uint8
+
+const (
+	ECNNotECT ECN = 0
+	ECNECT1   ECN = 1
+	ECNECT0   ECN = 2
+	ECNCE     ECN = 3
+)
+
+func (e ECN) String() string {
+	switch e {
+	case ECNNotECT:
+		return "Not-ECT"
+	case ECNECT1:
+		return "ECT(1)"
+	case ECNECT0:
+		return "ECT(0)"
+	case ECNCE:
+		return "CE"
+	default:
+		return fmt.Sprintf("%d", uint8(e))
+	}
+}
+
+func (h *Header) GetDSCP() DSCP {
+	return DSCP(h.ToS >> dscpShift)
+}
+
+func (h *Header) GetECN() ECN {
+	return ECN(h.ToS & ecnMask)
+}
+
+// SetDSCP
+// sets 6 high bits of ToS. ECN bits are kept
+// Only 6 low bits of dscp are used
+func (h *Header) SetDSCP(dscp DSCP) {
+	h.ToS = uint8(dscp&dscpMask)<<dscpShift | h.ToS&ecnMask
+}
+
+// SetECN
+// sets 2 low bits of ToS. DSCP bits are kept
+// Only 2 low bits of ecn are used
+func (h *Header) SetECN(ecn ECN) {
+	h.ToS = h.ToS&^ecnMask | uint8(ecn&ecnMask)
+}
@@ -0,0 +1,74 @@
+// Copyright 2026
+// license that can be found in the LICENSE file.
+
+package netpacket
+
+import "slices"
+
+// ParsePolicy
+// selects how parsers handle protocol violations which do not prevent parsing
+// Violations which never were rejected (for example trailing data) are
+// reported as anomalies with any policy
+type ParsePolicy uint8
+
+const (
+	// ParsePolicyStrict
+	// reject packets with protocol violations. Default policy
+	ParsePolicyStrict ParsePolicy = iota
+	// ParsePolicyLenient
+	// accept packets with protocol violations which do not prevent parsing
+	// Violations are reported as anomalies of parsed packet
+	ParsePolicyLenient
+)
+
+// ParseOptions
+// additional parsing settings. Zero value keeps default parsers behavior
+type ParseOptions struct {
+	// VerifyChecksum
+	// reject packets with invalid checksum with ErrBadChecksum error
+	// Not computed (zero) IPv4 header checksum is not rejected, it is set by NIC checksum offload
+	// Transport parsers ignore it because pseudo-header is required,
+	// use VerifyChecksum methods of transport packets instead.
+	// Transport decoded from IPv4 packet is verified with its pseudo-header and
+	// checksum status is saved in transport without rejecting it
+	VerifyChecksum bool
+	// Copy
+	// copy data before parsing, so parsed packet does not alias caller buffer
+	// and can be retained after buffer reuse
+	Copy bool
+	// Policy
+	// handling of protocol violations. Strict by default
+	Policy ParsePolicy
+	// AllowTruncated
+	// decode packets which length field exceeds data length, for example
+	// captured with small snaplen. Parsed packets are marked as truncated
+	AllowTruncated bool
+}
+
+// PrepareData
+// returns copy of data if Copy is set or data as is
+func (o ParseOptions) PrepareData(data []byte) []byte {
+	if o.Copy {
+		return slices.Clone(data)
+	}
+
+	return data
+}
+
+// Violation
+// returns err for strict policy. For lenient policy err is added
+// to anomalies as anomaly with code and nil returns
+func (o ParseOptions) Violation(anomalies *Anomalies, code AnomalyCode, err *ParseError) error {
+	if o.Policy == ParsePolicyStrict {
+		return err
+	}
+
+	anomalies.Add(Anomaly{
+		Code:    code,
+		Layer:   err.Kind,
+		Offset:  err.Offset,
+		Message: err.mismatch(),
+	})
+
+	return nil
+}
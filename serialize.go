@@ -0,0 +1,169 @@
+// Copyright 2026
+// license that can be found in the LICENSE file.
+
+package netpacket
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/name212/netpacket/checksum"
+)
+
+// SerializeOptions
+// additional serialization settings. Zero value writes all fields as is
+type SerializeOptions struct {
+	// FixLengths
+	// recalculate length fields from actual header and payload lengths
+	FixLengths bool
+	// ComputeChecksums
+	// recalculate checksum fields
+	ComputeChecksums bool
+	// PseudoHeader
+	// IPv4 pseudo-header for transport checksums calculation
+	// SerializeLayers sets it from the nearest NetworkLayer below transport layer
+	PseudoHeader *checksum.IPv4PseudoHeader
+}
+
+// SerializableLayer
+// layer which can write itself in front of already serialized upper layers
+// SerializeTo should treat current buffer bytes as layer payload
+type SerializableLayer interface {
+	SerializeTo(b *SerializeBuffer, opts SerializeOptions) error
+}
+
+// NetworkLayer
+// layer which provides pseudo-header for checksums of carried transport layer
+type NetworkLayer interface {
+	PseudoHeader() *checksum.IPv4PseudoHeader
+}
+
+// SerializeBuffer
+// buffer for serializing layers from upper to lower
+// Headers are prepended before already written bytes without copying payload
+// if buffer has enough space in front
+type SerializeBuffer struct {
+	data  []byte
+	start int
+
+	prependCapacity int
+}
+
+func NewSerializeBuffer() *SerializeBuffer {
+	return &SerializeBuffer{}
+}
+
+// NewSerializeBufferExpectedSize
+// creates buffer with preallocated space for prepended headers and appended payload
+func NewSerializeBufferExpectedSize(expectedPrependLength, expectedAppendLength int) *SerializeBuffer {
+	expectedPrependLength = max(expectedPrependLength, 0)
+	expectedAppendLength = max(expectedAppendLength, 0)
+
+	return &SerializeBuffer{
+		data:            make([]byte, expectedPrependLength, expectedPrependLength+expectedAppendLength),
+		start:           expectedPrependLength,
+		prependCapacity: expectedPrependLength,
+	}
+}
+
+// Bytes
+// returns serialized bytes. Returns subslice of internal buffer
+// which is valid until next buffer modification
+func (b *SerializeBuffer) Bytes() []byte {
+	return b.data[b.start:]
+}
+
+// PrependBytes
+// returns slice with num bytes in front of current bytes for writing
+// Content of returned slice is undefined
+func (b *SerializeBuffer) PrependBytes(num int) ([]byte, error) {
+	if num < 0 {
+		return nil, WrapCannotSerializeErr(fmt.Errorf("cannot prepend negative bytes count %d", num))
+	}
+
+	if b.start < num {
+		// grow space in front at least twice to amortize next prepends
+		prependLen := max(num-b.start, b.prependCapacity, len(b.data)-b.start)
+		newData := make([]byte, prependLen+len(b.data), prependLen+cap(b.data))
+		copy(newData[prependLen:], b.data)
+
+		b.data = newData
+		b.start += prependLen
+		b.prependCapacity += prependLen
+	}
+
+	b.start -= num
+
+	return b.data[b.start : b.start+num], nil
+}
+
+// AppendBytes
+// returns slice with num bytes after current bytes for writing
+// Content of returned slice is undefined
+func (b *SerializeBuffer) AppendBytes(num int) ([]byte, error) {
+	if num < 0 {
+		return nil, WrapCannotSerializeErr(fmt.Errorf("cannot append negative bytes count %d", num))
+	}
+
+	end := len(b.data)
+	b.data = slices.Grow(b.data, num)[:end+num]
+
+	return b.data[end:], nil
+}
+
+// Clear
+// resets buffer for reusing without releasing memory
+func (b *SerializeBuffer) Clear() {
+	b.start = b.prependCapacity
+	b.data = b.data[:b.start]
+}
+
+// SerializeLayers
+// clears buffer and serializes layers from last to first,
+// so lengths and checksums are fixed bottom-up
+// Each layer gets opts.PseudoHeader from the nearest NetworkLayer before it,
+// opts.PseudoHeader is kept for layers without NetworkLayer before them
+func SerializeLayers(b *SerializeBuffer, opts SerializeOptions, layers ...SerializableLayer) error {
+	b.Clear()
+
+	for i := len(layers) - 1; i >= 0; i-- {
+		layerOpts := opts
+		if pseudoHeader := lowerPseudoHeader(layers[:i]); pseudoHeader != nil {
+			layerOpts.PseudoHeader = pseudoHeader
+		}
+
+		if err := layers[i].SerializeTo(b, layerOpts); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// lowerPseudoHeader
+// returns pseudo-header of the last NetworkLayer in lower layers
+// returns nil if there is no NetworkLayer
+func lowerPseudoHeader(lower []SerializableLayer) *checksum.IPv4PseudoHeader {
+	for i := len(lower) - 1; i >= 0; i-- {
+		if network, ok := lower[i].(NetworkLayer); ok {
+			return network.PseudoHeader()
+		}
+	}
+
+	return nil
+}
+
+// Payload
+// raw application data layer
+type Payload []byte
+
+func (p Payload) SerializeTo(b *SerializeBuffer, _ SerializeOptions) error {
+	bytes, err := b.PrependBytes(len(p))
+	if err != nil {
+		return err
+	}
+
+	copy(bytes, p)
+
+	return nil
+}
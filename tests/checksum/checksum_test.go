@@ -0,0 +1,60 @@
+// Copyright 2026
+// license that can be found in the LICENSE file.
+
+package checksum
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/name212/netpacket/checksum"
+)
+
+func TestChecksum(t *testing.T) {
+	// example from RFC 1071
+	data := []byte{0x00, 0x01, 0xf2, 0x03, 0xf4, 0xf5, 0xf6, 0xf7}
+
+	require.Equal(t, uint32(0x2ddf0), checksum.Sum(data, 0), "partial sum should not be folded")
+	require.Equal(t, uint16(0x220d), checksum.Checksum(data), "checksum should be 0x220d")
+}
+
+func TestChecksumByParts(t *testing.T) {
+	data := []byte{0x45, 0x00, 0x00, 0x54, 0xbf, 0x08, 0x40, 0x00, 0x40, 0x01, 0x11}
+
+	sum := checksum.Sum(data[:4], 0)
+	sum = checksum.Sum(data[4:], sum)
+
+	require.Equal(t, checksum.Checksum(data), checksum.Fold(sum), "sum by parts should be equal to full sum")
+}
+
+func TestChecksumOddLength(t *testing.T) {
+	require.Equal(t, checksum.Checksum([]byte{0x01, 0x02, 0x03, 0x00}), checksum.Checksum([]byte{0x01, 0x02, 0x03}))
+	require.Equal(t, uint16(0xFFFF), checksum.Checksum(nil), "checksum of empty data should be 0xFFFF")
+}
+
+func TestIPv4PseudoHeaderChecksum(t *testing.T) {
+	pseudoHeader := checksum.IPv4PseudoHeader{
+		Source:      net.IPv4(10, 233, 233, 1),
+		Destination: net.IPv4(216, 58, 206, 46),
+		Protocol:    6,
+	}
+
+	// TCP segment without checksum
+	segment := []byte{
+		0xa7, 0x9e, 0x00, 0x50, 0x00, 0x4d, 0x6b, 0xcc, 0xb7, 0x16, 0x2a, 0x58,
+		0x50, 0x18, 0xfa, 0xf0, 0x00, 0x00, 0x00, 0x00, 0x47, 0x45, 0x54, 0x0d,
+		0x0a,
+	}
+
+	require.Equal(t, uint16(0x7fb9), pseudoHeader.Checksum(segment), "checksum should be computed")
+	require.Equal(t, uint16(0x7fb9), pseudoHeader.Checksum(segment[:20], segment[20:]), "checksum by parts should be equal")
+}
+
+func TestStatusString(t *testing.T) {
+	require.Equal(t, "not verified", checksum.StatusNotVerified.String())
+	require.Equal(t, "valid", checksum.StatusValid.String())
+	require.Equal(t, "invalid", checksum.StatusInvalid.String())
+	require.Equal(t, "not computed", checksum.StatusNotComputed.String())
+}
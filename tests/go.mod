@@ -3,13 +3,14 @@ module github.com/name212/netpacket/tests
 go 1.25.5
 
 require (
-    github.com/name212/netpacket v0.0.0-00010101000000-000000000000
+	github.com/name212/netpacket v0.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.11.1
+)
+
+require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	github.com/stretchr/testify v1.11.1 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
-replace (
-	github.com/name212/netpacket => ../
-)
+replace github.com/name212/netpacket => ../
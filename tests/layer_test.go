@@ -0,0 +1,40 @@
+// Copyright 2026
+// license that can be found in the LICENSE file.
+
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/name212/netpacket"
+)
+
+func TestDecodedPacket(t *testing.T) {
+	pkt := netpacket.NewDecodedPacket()
+	require.Empty(t, pkt.Layers())
+	require.Nil(t, pkt.ApplicationPayload())
+
+	_, ok := netpacket.LayerOf[netpacket.Payload](pkt)
+	require.False(t, ok, "empty packet should not contain payload")
+
+	payload := netpacket.Payload("hello")
+	pkt.AddLayer(payload)
+
+	require.Equal(t, netpacket.KindPayload, payload.Kind())
+	require.Equal(t, []byte("hello"), payload.Contents())
+	require.Nil(t, payload.Payload(), "payload should not carry another layer")
+	require.Empty(t, payload.NextLayerKind())
+
+	found, ok := netpacket.LayerOf[netpacket.Payload](pkt)
+	require.True(t, ok)
+	require.Equal(t, payload, found)
+	require.Equal(t, payload, pkt.Layer(netpacket.KindPayload))
+	require.Equal(t, []byte("hello"), pkt.ApplicationPayload())
+
+	AssertStringer(t, pkt, `
+Decoded packet:
+	Payload len: 5
+`)
+}
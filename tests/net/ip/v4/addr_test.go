@@ -0,0 +1,140 @@
+// Copyright 2026
+// license that can be found in the LICENSE file.
+
+package v4
+
+import (
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/name212/netpacket"
+	"github.com/name212/netpacket/net/ip/v4"
+	"github.com/name212/netpacket/tests"
+)
+
+func TestIPv4Addr(t *testing.T) {
+	packet := parsePacket(t, udpPacketData, 56, 36)
+
+	source := packet.GetSourceAddr()
+	destination := packet.GetDestinationAddr()
+
+	require.True(t, source.Is4(), "source should be IPv4 address")
+	require.Equal(t, packet.GetSourceIPString(), source.String())
+	require.Equal(t, packet.GetDestinationIPString(), destination.String())
+
+	flows := map[netip.Addr]int{source: 1}
+	require.Equal(t, 1, flows[netip.MustParseAddr(packet.GetSourceIPString())], "addr should be usable as map key")
+
+	header := validHeaderForSerialize()
+	header.SourceIP = net.IPv4(10, 1, 1, 1)
+	require.Equal(t, netip.MustParseAddr("10.1.1.1"), header.GetSourceAddr(), "16 bytes IPv4 should be converted")
+
+	header.SourceIP = nil
+	require.False(t, header.GetSourceAddr().IsValid(), "should return zero addr for not set IP")
+}
+
+func TestIPv4SetAddr(t *testing.T) {
+	header := validHeaderForSerialize()
+
+	require.NoError(t, header.SetSourceAddr(netip.MustParseAddr("192.168.1.1")))
+	require.NoError(t, header.SetDestinationAddr(netip.MustParseAddr("::ffff:192.168.1.2")))
+	require.Equal(t, "192.168.1.1", header.GetSourceIPString())
+	require.Equal(t, "192.168.1.2", header.GetDestinationIPString(), "IPv4-mapped address should be unmapped")
+
+	err := header.SetSourceAddr(netip.MustParseAddr("::1"))
+	require.Error(t, err, "should not set IPv6 address")
+	require.Equal(t, "192.168.1.1", header.GetSourceIPString(), "should keep address on error")
+
+	require.Error(t, header.SetDestinationAddr(netip.Addr{}), "should not set zero address")
+}
+
+func TestIPv4AddrPorts(t *testing.T) {
+	t.Run("UDP", func(t *testing.T) {
+		packet := parsePacket(t, udpPacketData, 56, 36)
+
+		source, destination, err := packet.AddrPorts()
+		require.NoError(t, err, "should extract addr ports")
+		require.Equal(t, netip.AddrPortFrom(packet.GetSourceAddr(), 39290), source)
+		require.Equal(t, netip.AddrPortFrom(packet.GetDestinationAddr(), 53), destination)
+
+		transport, err := packet.TransportPacket()
+		require.NoError(t, err)
+
+		sourceWith, destinationWith := packet.AddrPortsWith(transport)
+		require.Equal(t, source, sourceWith, "should be same as from transport")
+		require.Equal(t, destination, destinationWith, "should be same as from transport")
+	})
+
+	t.Run("TCP", func(t *testing.T) {
+		packet := parsePacket(t, tcpPacketData, 113, 93)
+
+		source, destination, err := packet.AddrPorts()
+		require.NoError(t, err, "should extract addr ports")
+		require.Equal(t, uint16(42910), source.Port())
+		require.Equal(t, uint16(80), destination.Port())
+	})
+
+	t.Run("Not transport", func(t *testing.T) {
+		_, _, err := icmpValidPacket(t).AddrPorts()
+		require.ErrorIs(t, err, v4.ErrNotTransportPacket, "should not extract ports from ICMP")
+	})
+
+	t.Run("Not first fragment", func(t *testing.T) {
+		data := append([]byte{}, udpPacketData...)
+		packet := parsePacket(t, data, 56, 36)
+		packet.GetHeader().FragmentOffset = 1
+
+		_, _, err := packet.AddrPorts()
+		require.ErrorIs(t, err, v4.ErrNotFirstFragment, "should not extract ports from not first fragment")
+		require.NotErrorIs(t, err, v4.ErrNotTransportPacket, "fragment carries transport protocol")
+		require.Contains(t, err.Error(), "UDP fragment with offset 1 does not contain ports")
+	})
+
+	t.Run("Short payload", func(t *testing.T) {
+		header := validHeaderForSerialize()
+		packet := v4.NewPacket(header, []byte{0x00, 0x35})
+
+		_, _, err := packet.AddrPorts()
+		tests.AssertParseError(t, err, netpacket.ErrShortData, netpacket.ParseError{
+			Kind:     v4.Kind,
+			Field:    "UDP ports",
+			Offset:   20,
+			Expected: ">= 4",
+			Actual:   2,
+		})
+	})
+}
+
+func TestIPv4ParseIntoWithoutAllocations(t *testing.T) {
+	header := &v4.Header{}
+	packet := &v4.Packet{}
+
+	// first call allocates packet header
+	require.NoError(t, v4.ParsePacketInto(tcpPacketData, netpacket.ParseOptions{}, packet))
+
+	allocs := testing.AllocsPerRun(100, func() {
+		if err := v4.ParseHeaderInto(udpPacketData, header); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := v4.ParsePacketInto(udpPacketData, netpacket.ParseOptions{VerifyChecksum: true}, packet); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, _, err := packet.AddrPorts(); err != nil {
+			t.Fatal(err)
+		}
+
+		_ = header.GetSourceAddr()
+		_ = header.GetDestinationAddr()
+	})
+
+	require.Zero(t, allocs, "parsing into reused header and packet should not allocate")
+
+	require.Equal(t, "UDP", header.ProtocolString(), "header should be overwritten")
+	require.Equal(t, v4.ProtocolUDP, packet.GetProtocol(), "packet should be overwritten")
+	require.Len(t, packet.GetPayload(), 36)
+}
@@ -0,0 +1,154 @@
+// Copyright 2026
+// license that can be found in the LICENSE file.
+
+package v4
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/name212/netpacket"
+	"github.com/name212/netpacket/net/ip/v4"
+)
+
+func TestIPv4ParseAnomalies(t *testing.T) {
+	validPacket := func(t *testing.T) []byte {
+		t.Helper()
+
+		data, err := v4.NewPacket(validHeaderForSerialize(), []byte{0x01, 0x02, 0x03, 0x04}).Serialize(netpacket.SerializeOptions{
+			FixLengths:       true,
+			ComputeChecksums: true,
+		})
+		require.NoError(t, err)
+
+		return data
+	}
+
+	type testCase struct {
+		name     string
+		modify   func(data []byte) []byte
+		code     netpacket.AnomalyCode
+		offset   int
+		rejected bool
+	}
+
+	testCases := []testCase{
+		{
+			name: "bad version",
+			modify: func(data []byte) []byte {
+				data[0] = 0x65
+				return data
+			},
+			code:     netpacket.AnomalyBadVersion,
+			offset:   0,
+			rejected: true,
+		},
+		{
+			name: "reserved flag",
+			modify: func(data []byte) []byte {
+				data[6] |= 0x80
+				return data
+			},
+			code:     netpacket.AnomalyReservedBits,
+			offset:   6,
+			rejected: true,
+		},
+		{
+			name: "header length exceeds total length",
+			modify: func(data []byte) []byte {
+				data[0] = 0x46
+				data[3] = 0x14
+				return append(data, 0x00, 0x00, 0x00, 0x00)
+			},
+			code:     netpacket.AnomalyInvalidLength,
+			offset:   0,
+			rejected: true,
+		},
+		{
+			name: "total length too small",
+			modify: func(data []byte) []byte {
+				data[3] = 0x10
+				return data
+			},
+			code:     netpacket.AnomalyInvalidLength,
+			offset:   2,
+			rejected: true,
+		},
+		{
+			name: "trailing data",
+			modify: func(data []byte) []byte {
+				return append(data, 0x00, 0x00)
+			},
+			code:   netpacket.AnomalyTrailingData,
+			offset: 24,
+		},
+	}
+
+	lenient := netpacket.ParseOptions{Policy: netpacket.ParsePolicyLenient}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data := tc.modify(validPacket(t))
+
+			strictPacket, err := v4.ParsePacket(data)
+			if tc.rejected {
+				require.Error(t, err, "strict policy should reject packet")
+				require.ErrorIs(t, err, netpacket.ErrCannotParseHeader)
+			} else {
+				require.NoError(t, err, "strict policy should accept packet")
+				require.True(t, strictPacket.Anomalies().Has(tc.code), "strict policy should report anomaly")
+			}
+
+			packet, err := v4.ParsePacketWithOptions(data, lenient)
+			require.NoError(t, err, "lenient policy should accept packet")
+
+			anomalies := packet.Anomalies()
+			require.True(t, anomalies.Has(tc.code), "should report %s", tc.code)
+
+			idx := slices.IndexFunc(anomalies, func(a netpacket.Anomaly) bool {
+				return a.Code == tc.code
+			})
+			require.Equal(t, v4.Kind, anomalies[idx].Layer, "anomaly layer should be IPv4")
+			require.Equal(t, tc.offset, anomalies[idx].Offset, "anomaly offset")
+			require.NotEmpty(t, anomalies[idx].Message, "anomaly should have message")
+			require.Contains(t, packet.String(), "\tAnomalies:\n\t\tIPv4 "+tc.code.String())
+		})
+	}
+
+	t.Run("valid packet", func(t *testing.T) {
+		packet, err := v4.ParsePacketWithOptions(validPacket(t), lenient)
+		require.NoError(t, err)
+		require.Empty(t, packet.Anomalies(), "valid packet should not have anomalies")
+		require.NotContains(t, packet.String(), "Anomalies:")
+	})
+
+	t.Run("truncated options", func(t *testing.T) {
+		data := validPacket(t)
+		data[0] = 0x4F
+
+		packet, err := v4.ParsePacketWithOptions(data, lenient)
+		require.NoError(t, err)
+
+		anomalies := packet.Anomalies()
+		require.True(t, anomalies.Has(netpacket.AnomalyInvalidLength), "header length exceeds total length")
+		require.True(t, anomalies.Has(netpacket.AnomalyTruncatedOptions), "options should be truncated")
+		require.Empty(t, packet.GetHeader().Options, "truncated options should not be parsed")
+		require.Equal(t, data, packet.GetHeaderData(), "header data should be limited by data")
+		require.Empty(t, packet.GetPayload())
+	})
+
+	t.Run("anomalies are reset for reused packet", func(t *testing.T) {
+		packet := &v4.Packet{}
+
+		data := validPacket(t)
+		data[0] = 0x65
+
+		require.NoError(t, v4.ParsePacketInto(data, lenient, packet))
+		require.Len(t, packet.Anomalies(), 1)
+
+		require.NoError(t, v4.ParsePacketInto(validPacket(t), lenient, packet))
+		require.Empty(t, packet.Anomalies())
+	})
+}
@@ -0,0 +1,193 @@
+// Copyright 2026
+// license that can be found in the LICENSE file.
+
+package v4
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/name212/netpacket"
+	"github.com/name212/netpacket/checksum"
+	"github.com/name212/netpacket/net/ip/v4"
+	"github.com/name212/netpacket/transport/udp"
+)
+
+func TestIPv4HeaderChecksum(t *testing.T) {
+	t.Run("valid without options", func(t *testing.T) {
+		header := parseHeader(t, icmpValidPacketData, 84)
+
+		require.Equal(t, uint16(30630), header.ComputeChecksum(), "computed checksum should be 30630")
+		require.True(t, header.VerifyChecksum(), "checksum should be valid")
+	})
+
+	t.Run("valid with options", func(t *testing.T) {
+		data := append([]byte{}, icmpValidPacketData...)
+		packet := parsePacket(t, data, 84, 64)
+		header := packet.GetHeader()
+
+		header.Options = []byte{0x94, 0x04, 0x00, 0x00}
+		header.IHL = 6
+		header.Checksum = header.ComputeChecksum()
+
+		require.Equal(t, uint16(0xe2a1), header.Checksum, "computed checksum with options")
+		require.True(t, header.VerifyChecksum(), "checksum should be valid")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		data := append([]byte{}, icmpValidPacketData...)
+		// change TTL
+		data[8] = 0x3f
+
+		header := parseHeader(t, data, 84)
+		require.False(t, header.VerifyChecksum(), "checksum should be invalid")
+	})
+}
+
+func TestIPv4ChecksumStatus(t *testing.T) {
+	assertStatus := func(t *testing.T, data []byte, expected checksum.Status) {
+		t.Helper()
+
+		packet := parsePacket(t, data, 84, 64)
+		require.Equal(t, expected, packet.ChecksumStatus(), "packet checksum should be %s", expected)
+		require.Equal(t, expected, packet.GetHeader().ChecksumStatus(), "header checksum should be %s", expected)
+	}
+
+	assertStatus(t, icmpValidPacketData, checksum.StatusValid)
+
+	offloaded := append([]byte{}, icmpValidPacketData...)
+	offloaded[10] = 0x00
+	offloaded[11] = 0x00
+	assertStatus(t, offloaded, checksum.StatusNotComputed)
+
+	corrupted := append([]byte{}, icmpValidPacketData...)
+	// change TTL
+	corrupted[8] = 0x3f
+	assertStatus(t, corrupted, checksum.StatusInvalid)
+
+	t.Run("verify checksum option", func(t *testing.T) {
+		opts := netpacket.ParseOptions{VerifyChecksum: true}
+
+		packet, err := v4.ParsePacketWithOptions(offloaded, opts)
+		require.NoError(t, err, "should not reject not computed checksum")
+		require.Equal(t, checksum.StatusNotComputed, packet.ChecksumStatus(), "checksum should be not computed")
+
+		_, err = v4.ParseHeaderWithOptions(offloaded, opts)
+		require.NoError(t, err, "should not reject header with not computed checksum")
+
+		_, err = v4.ParsePacketWithOptions(corrupted, opts)
+		require.ErrorIs(t, err, netpacket.ErrBadChecksum, "should reject invalid checksum")
+
+		_, err = v4.ParseHeaderWithOptions(corrupted, opts)
+		require.ErrorIs(t, err, netpacket.ErrBadChecksum, "should reject header with invalid checksum")
+	})
+
+	t.Run("over header data", func(t *testing.T) {
+		data := append([]byte{}, icmpValidPacketData...)
+		packet := parsePacket(t, data, 84, 64)
+
+		packet.GetHeader().TTL = 1
+
+		require.Equal(t, uint16(30630), packet.ComputeChecksum(), "should compute checksum over header data")
+		require.Equal(t, checksum.StatusValid, packet.ChecksumStatus(), "header data checksum should be valid")
+		require.Equal(t, checksum.StatusInvalid, packet.GetHeader().ChecksumStatus(), "changed header checksum should be invalid")
+	})
+
+	t.Run("created packet", func(t *testing.T) {
+		header := parseHeader(t, icmpValidPacketData, 84).Clone()
+		packet := v4.NewPacket(header, nil)
+
+		require.Equal(t, header.ComputeChecksum(), packet.ComputeChecksum(), "should compute checksum over header fields")
+		require.Equal(t, checksum.StatusValid, packet.ChecksumStatus(), "checksum should be valid")
+	})
+}
+
+func TestParseIPv4PacketVerifyChecksum(t *testing.T) {
+	opts := netpacket.ParseOptions{VerifyChecksum: true}
+
+	packet, err := v4.ParsePacketWithOptions(icmpValidPacketData, opts)
+	require.NoError(t, err, "should parse packet with valid checksum")
+	require.NotNil(t, packet)
+
+	data := append([]byte{}, icmpValidPacketData...)
+	data[10] = 0x00
+	data[11] = 0x01
+
+	_, err = v4.ParsePacket(data)
+	require.NoError(t, err, "should parse packet with invalid checksum without verification")
+
+	packet, err = v4.ParsePacketWithOptions(data, opts)
+	require.Error(t, err, "should not parse packet with invalid checksum")
+	require.ErrorIs(t, err, netpacket.ErrBadChecksum, "should be bad checksum error")
+	require.Contains(t, err.Error(), "IPv4 header checksum 1, computed 30630: invalid")
+	require.Nil(t, packet)
+}
+
+func TestVerifyTransportChecksum(t *testing.T) {
+	t.Run("Not transport", func(t *testing.T) {
+		packet := icmpValidPacket(t)
+
+		transport, status, err := packet.VerifyTransportChecksum()
+		require.ErrorIs(t, err, v4.ErrNotTransportPacket, "should not verify not transport packet")
+		require.Equal(t, checksum.StatusNotVerified, status)
+		require.Nil(t, transport)
+	})
+
+	t.Run("TCP valid", func(t *testing.T) {
+		packet := parsePacket(t, tcpPacketData, 113, 93)
+
+		transport, status, err := packet.VerifyTransportChecksum()
+		require.NoError(t, err, "should verify")
+		require.Equal(t, checksum.StatusValid, status, "checksum should be valid")
+
+		tcpPacket, ok := v4.ToTCP(transport)
+		require.True(t, ok, "should return TCP packet")
+		require.Equal(t, checksum.StatusValid, tcpPacket.ChecksumStatus(), "returned packet should keep checksum status")
+	})
+
+	t.Run("UDP", func(t *testing.T) {
+		assertUDPStatus := func(t *testing.T, checksumBytes []byte, expected checksum.Status) {
+			t.Helper()
+
+			data := append([]byte{}, udpPacketData...)
+			copy(data[26:28], checksumBytes)
+
+			packet := parsePacket(t, data, 56, 36)
+			transport, status, err := packet.VerifyTransportChecksum()
+			require.NoError(t, err, "should verify")
+			require.Equal(t, expected, status, "checksum should be %s", expected)
+
+			datagram, ok := v4.ToUDP(transport)
+			require.True(t, ok, "should return UDP datagram")
+			require.Equal(t, expected, datagram.ChecksumStatus(), "returned datagram should keep checksum status")
+		}
+
+		assertUDPStatus(t, []byte{0xbe, 0x5b}, checksum.StatusInvalid)
+		assertUDPStatus(t, []byte{0x51, 0xf5}, checksum.StatusValid)
+		assertUDPStatus(t, []byte{0x00, 0x00}, checksum.StatusNotComputed)
+	})
+}
+
+func TestDecodeVerifyTransportChecksum(t *testing.T) {
+	assertDecodedStatus := func(t *testing.T, opts netpacket.ParseOptions, checksumBytes []byte, expected checksum.Status) {
+		t.Helper()
+
+		data := append([]byte{}, udpPacketData...)
+		copy(data[26:28], checksumBytes)
+
+		decoded, err := v4.Decode(data, opts)
+		require.NoError(t, err, "should decode packet")
+
+		datagram, ok := netpacket.LayerOf[*udp.Datagram](decoded)
+		require.True(t, ok, "should decode UDP datagram")
+		require.Equal(t, expected, datagram.ChecksumStatus(), "checksum status should be %s", expected)
+	}
+
+	verify := netpacket.ParseOptions{VerifyChecksum: true}
+
+	assertDecodedStatus(t, netpacket.ParseOptions{}, []byte{0x51, 0xf5}, checksum.StatusNotVerified)
+	assertDecodedStatus(t, verify, []byte{0x51, 0xf5}, checksum.StatusValid)
+	assertDecodedStatus(t, verify, []byte{0xbe, 0x5b}, checksum.StatusInvalid)
+	assertDecodedStatus(t, verify, []byte{0x00, 0x00}, checksum.StatusNotComputed)
+}
@@ -0,0 +1,73 @@
+// Copyright 2026
+// license that can be found in the LICENSE file.
+
+package v4
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/name212/netpacket"
+	"github.com/name212/netpacket/net/ip/v4"
+	"github.com/name212/netpacket/tests"
+)
+
+func TestIPv4ParseWithCopy(t *testing.T) {
+	data := slices.Clone(tcpPacketData)
+
+	packet, err := v4.ParsePacketWithOptions(data, netpacket.ParseOptions{Copy: true, VerifyChecksum: true})
+	require.NoError(t, err, "should parse packet")
+
+	header, err := v4.ParseHeaderWithOptions(data, netpacket.ParseOptions{Copy: true})
+	require.NoError(t, err, "should parse header")
+
+	expectedPayload := slices.Clone(packet.GetPayload())
+	sourceIP := packet.GetSourceIPString()
+
+	tests.OverwriteBuffer(data)
+
+	require.Equal(t, sourceIP, packet.GetSourceIPString(), "packet should not alias data")
+	require.Equal(t, sourceIP, header.GetSourceIPString(), "header should not alias data")
+	require.Equal(t, expectedPayload, packet.GetPayload(), "payload should not alias data")
+	require.True(t, packet.GetHeader().VerifyChecksum(), "header data should not alias data")
+}
+
+func TestIPv4ParseHeaderWithOptionsBadChecksum(t *testing.T) {
+	data := slices.Clone(tcpPacketData)
+	data[10]++
+
+	_, err := v4.ParseHeaderWithOptions(data, netpacket.ParseOptions{VerifyChecksum: true})
+	require.ErrorIs(t, err, netpacket.ErrBadChecksum, "should verify checksum")
+}
+
+func TestIPv4Clone(t *testing.T) {
+	data := slices.Clone(udpPacketData)
+
+	packet, err := v4.ParsePacket(data)
+	require.NoError(t, err, "should parse packet")
+
+	clone := packet.Clone()
+	require.Equal(t, packet.String(), clone.String(), "clone should be equal to packet")
+
+	expectedPayload := slices.Clone(packet.GetPayload())
+	expectedHeaderData := slices.Clone(packet.GetHeaderData())
+
+	tests.OverwriteBuffer(data)
+
+	require.Equal(t, expectedPayload, clone.GetPayload(), "payload should be copied")
+	require.Equal(t, expectedHeaderData, clone.GetHeaderData(), "header data should be copied")
+	require.Equal(t, "172.17.0.3", clone.GetSourceIPString(), "source IP should be copied")
+	require.NotEqual(t, "172.17.0.3", packet.GetSourceIPString(), "original packet should alias data")
+
+	header := validHeaderForSerialize()
+	header.Options = []byte{0x94, 0x04, 0x00, 0x00}
+
+	headerClone := header.Clone()
+	header.Options[0] = 0x01
+	header.SourceIP[len(header.SourceIP)-4] = 0x01
+
+	require.Equal(t, []byte{0x94, 0x04, 0x00, 0x00}, headerClone.Options, "options should be copied")
+	require.Equal(t, "10.0.0.1", headerClone.GetSourceIPString(), "IP should be copied")
+}
@@ -0,0 +1,123 @@
+// Copyright 2026
+// license that can be found in the LICENSE file.
+
+package v4
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/name212/netpacket"
+	"github.com/name212/netpacket/net/ip/v4"
+	"github.com/name212/netpacket/transport/tcp"
+	"github.com/name212/netpacket/transport/udp"
+)
+
+func TestDecodeIPv4(t *testing.T) {
+	layerKinds := func(pkt *netpacket.DecodedPacket) []netpacket.Kind {
+		kinds := make([]netpacket.Kind, 0, len(pkt.Layers()))
+		for _, layer := range pkt.Layers() {
+			kinds = append(kinds, layer.Kind())
+		}
+
+		return kinds
+	}
+
+	t.Run("UDP", func(t *testing.T) {
+		pkt, err := v4.Decode(udpPacketData, netpacket.ParseOptions{})
+		require.NoError(t, err)
+		require.Equal(t, []netpacket.Kind{v4.Kind, udp.Kind, netpacket.KindPayload}, layerKinds(pkt))
+
+		ip, ok := netpacket.LayerOf[*v4.Packet](pkt)
+		require.True(t, ok, "should contain IPv4 layer")
+		require.Equal(t, udp.Kind, ip.NextLayerKind())
+		require.Equal(t, udpPacketData[:20], ip.Contents())
+
+		datagram, ok := netpacket.LayerOf[*udp.Datagram](pkt)
+		require.True(t, ok, "should contain UDP layer")
+		require.Equal(t, 53, datagram.GetDestinationPort())
+		require.Equal(t, udpPacketData[20:28], datagram.Contents())
+		require.Equal(t, netpacket.KindPayload, datagram.NextLayerKind())
+
+		_, ok = netpacket.LayerOf[*tcp.Packet](pkt)
+		require.False(t, ok, "should not contain TCP layer")
+		require.Nil(t, pkt.Layer(tcp.Kind))
+		require.Equal(t, datagram, pkt.Layer(udp.Kind))
+
+		require.Equal(t, udpPacketData[28:], pkt.ApplicationPayload())
+	})
+
+	t.Run("TCP", func(t *testing.T) {
+		pkt, err := v4.Decode(tcpPacketData, netpacket.ParseOptions{Copy: true})
+		require.NoError(t, err)
+		require.Equal(t, []netpacket.Kind{v4.Kind, tcp.Kind, netpacket.KindPayload}, layerKinds(pkt))
+
+		segment, ok := netpacket.LayerOf[*tcp.Packet](pkt)
+		require.True(t, ok, "should contain TCP layer")
+		require.Equal(t, 80, segment.GetDestinationPort())
+		require.Equal(t, "GET / HTTP/1.1\r\n", string(pkt.ApplicationPayload()[:16]))
+	})
+
+	t.Run("not transport", func(t *testing.T) {
+		pkt, err := v4.Decode(icmpValidPacketData, netpacket.ParseOptions{})
+		require.NoError(t, err)
+		require.Equal(t, []netpacket.Kind{v4.Kind, netpacket.KindPayload}, layerKinds(pkt))
+		require.Len(t, pkt.ApplicationPayload(), 64)
+	})
+
+	udpFragments := func(t *testing.T) []*v4.Packet {
+		packet, err := v4.ParsePacket(slices.Clone(udpPacketData))
+		require.NoError(t, err)
+		packet.GetHeader().SetDontFragment(false)
+
+		fragments, err := v4.Fragment(packet, 36)
+		require.NoError(t, err)
+		require.Len(t, fragments, 3)
+
+		return fragments
+	}
+
+	t.Run("first fragment", func(t *testing.T) {
+		data, err := udpFragments(t)[0].MarshalBinary()
+		require.NoError(t, err)
+
+		pkt, err := v4.Decode(data, netpacket.ParseOptions{})
+		require.NoError(t, err)
+		require.Equal(t, []netpacket.Kind{v4.Kind, netpacket.KindPayload}, layerKinds(pkt))
+		require.Equal(t, data[20:], pkt.ApplicationPayload(), "should return fragment payload as is")
+	})
+
+	t.Run("not first fragment", func(t *testing.T) {
+		data, err := udpFragments(t)[1].MarshalBinary()
+		require.NoError(t, err)
+
+		pkt, err := v4.Decode(data, netpacket.ParseOptions{})
+		require.NoError(t, err)
+		require.Equal(t, []netpacket.Kind{v4.Kind, netpacket.KindPayload}, layerKinds(pkt))
+	})
+
+	t.Run("no payload", func(t *testing.T) {
+		pkt, err := v4.Decode(udpPacketData[:20], netpacket.ParseOptions{AllowTruncated: true})
+		require.NoError(t, err)
+		require.Equal(t, []netpacket.Kind{v4.Kind}, layerKinds(pkt))
+		require.Nil(t, pkt.ApplicationPayload())
+	})
+
+	t.Run("transport cannot be parsed", func(t *testing.T) {
+		pkt, err := v4.Decode(udpPacketData[:24], netpacket.ParseOptions{AllowTruncated: true})
+		require.ErrorIs(t, err, netpacket.ErrShortData)
+		require.Nil(t, pkt, "should not return partially decoded packet with error")
+
+		packet, err := v4.ParsePacketWithOptions(udpPacketData[:24], netpacket.ParseOptions{AllowTruncated: true})
+		require.NoError(t, err, "IPv4 packet should be parsed without transport")
+		require.Equal(t, udpPacketData[20:24], packet.GetPayload())
+	})
+
+	t.Run("IPv4 cannot be parsed", func(t *testing.T) {
+		pkt, err := v4.Decode(udpPacketData[:10], netpacket.ParseOptions{})
+		require.ErrorIs(t, err, netpacket.ErrShortData)
+		require.Nil(t, pkt)
+	})
+}
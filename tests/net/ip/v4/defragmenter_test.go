@@ -0,0 +1,384 @@
+// Copyright 2026
+// license that can be found in the LICENSE file.
+
+package v4
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/name212/netpacket"
+	"github.com/name212/netpacket/net/ip/v4"
+)
+
+var defragmenterNow = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestDefragmenterNotFragment(t *testing.T) {
+	defragmenter := v4.NewDefragmenter(v4.DefragmenterConfig{})
+
+	packet := icmpValidPacket(t)
+
+	res, err := defragmenter.DefragmentAt(packet, defragmenterNow)
+	require.NoError(t, err, "should not fail for not fragment")
+	require.Same(t, packet, res, "should return packet as is")
+	require.Equal(t, 0, defragmenter.PendingPackets(), "should not store not fragment")
+}
+
+func TestDefragmenterReassemble(t *testing.T) {
+	payload := fragmentsTestPayload(40)
+
+	fragments := []*v4.Packet{
+		newTestFragment(t, 1, 16, true, payload[16:32]),
+		newTestFragment(t, 1, 32, false, payload[32:40]),
+		newTestFragment(t, 1, 0, true, payload[0:16]),
+	}
+
+	defragmenter := v4.NewDefragmenter(v4.DefragmenterConfig{})
+
+	for _, fragment := range fragments[:2] {
+		res, err := defragmenter.DefragmentAt(fragment, defragmenterNow)
+		require.NoError(t, err, "should add fragment")
+		require.Nil(t, res, "should wait more fragments")
+	}
+
+	require.Equal(t, 1, defragmenter.PendingPackets(), "should store one incomplete packet")
+
+	res, err := defragmenter.DefragmentAt(fragments[2], defragmenterNow)
+	require.NoError(t, err, "should reassemble packet")
+	require.NotNil(t, res, "should return reassembled packet")
+	require.Equal(t, 0, defragmenter.PendingPackets(), "should not store reassembled packet")
+
+	header := res.GetHeader()
+	require.Equal(t, payload, res.GetPayload(), "payload should be reassembled")
+	require.Equal(t, 60, header.GetTotalLen(), "total length should be fixed")
+	require.Equal(t, uint16(0), header.FragmentOffset, "fragment offset should be zero")
+	require.False(t, header.MoreFragments(), "more fragments should be cleared")
+	require.True(t, header.VerifyChecksum(), "checksum should be recomputed")
+	assertSourceAndDestinationAndProto(t, header, "10.0.0.1", v4.ProtocolUDP, "10.0.0.2", "UDP")
+}
+
+func TestDefragmenterSeparatesPackets(t *testing.T) {
+	payload := fragmentsTestPayload(16)
+
+	defragmenter := v4.NewDefragmenter(v4.DefragmenterConfig{})
+
+	res, err := defragmenter.DefragmentAt(newTestFragment(t, 1, 0, true, payload[:8]), defragmenterNow)
+	require.NoError(t, err)
+	require.Nil(t, res)
+
+	res, err = defragmenter.DefragmentAt(newTestFragment(t, 2, 8, false, payload[8:]), defragmenterNow)
+	require.NoError(t, err)
+	require.Nil(t, res, "should not reassemble fragments with different identification")
+	require.Equal(t, 2, defragmenter.PendingPackets(), "should store two incomplete packets")
+}
+
+func TestDefragmenterOverlapPolicies(t *testing.T) {
+	// fragments in order of receiving:
+	// offset 0 len 8 with 0xEE without overlaps
+	// offset 8 len 16 with 0xAA is original for overlaps below
+	// offset 8 len 8 with 0xBB starts at the same offset as 0xAA
+	// offset 16 len 8 with 0xDD starts after 0xAA
+	// offset 32 len 8 with 0xFF is last
+	// offset 24 len 16 with 0xCC starts before 0xFF
+	fragments := func(t *testing.T) []*v4.Packet {
+		return []*v4.Packet{
+			newTestFragment(t, 1, 0, true, bytes.Repeat([]byte{0xEE}, 8)),
+			newTestFragment(t, 1, 8, true, bytes.Repeat([]byte{0xAA}, 16)),
+			newTestFragment(t, 1, 8, true, bytes.Repeat([]byte{0xBB}, 8)),
+			newTestFragment(t, 1, 16, true, bytes.Repeat([]byte{0xDD}, 8)),
+			newTestFragment(t, 1, 32, false, bytes.Repeat([]byte{0xFF}, 8)),
+			newTestFragment(t, 1, 24, false, bytes.Repeat([]byte{0xCC}, 16)),
+		}
+	}
+
+	testCases := []struct {
+		policy   v4.OverlapPolicy
+		expected []byte
+	}{
+		{
+			policy: v4.OverlapPolicyFirst,
+			expected: concatBytes(
+				bytes.Repeat([]byte{0xEE}, 8),
+				bytes.Repeat([]byte{0xAA}, 16),
+				bytes.Repeat([]byte{0xCC}, 8),
+				bytes.Repeat([]byte{0xFF}, 8),
+			),
+		},
+		{
+			policy: v4.OverlapPolicyLast,
+			expected: concatBytes(
+				bytes.Repeat([]byte{0xEE}, 8),
+				bytes.Repeat([]byte{0xBB}, 8),
+				bytes.Repeat([]byte{0xDD}, 8),
+				bytes.Repeat([]byte{0xCC}, 16),
+			),
+		},
+		{
+			policy: v4.OverlapPolicyBSD,
+			expected: concatBytes(
+				bytes.Repeat([]byte{0xEE}, 8),
+				bytes.Repeat([]byte{0xAA}, 16),
+				bytes.Repeat([]byte{0xCC}, 16),
+			),
+		},
+		{
+			policy: v4.OverlapPolicyLinux,
+			expected: concatBytes(
+				bytes.Repeat([]byte{0xEE}, 8),
+				bytes.Repeat([]byte{0xBB}, 8),
+				bytes.Repeat([]byte{0xAA}, 8),
+				bytes.Repeat([]byte{0xCC}, 16),
+			),
+		},
+	}
+
+	for _, tc := range testCases {
+		defragmenter := v4.NewDefragmenter(v4.DefragmenterConfig{OverlapPolicy: tc.policy})
+
+		res := defragmentAll(t, defragmenter, fragments(t))
+		require.Equal(t, tc.expected, res.GetPayload(), "payload should be reassembled with policy %d", tc.policy)
+	}
+
+	t.Run("same offset", func(t *testing.T) {
+		assertSameOffset := func(t *testing.T, policy v4.OverlapPolicy, expected byte) {
+			t.Helper()
+
+			defragmenter := v4.NewDefragmenter(v4.DefragmenterConfig{OverlapPolicy: policy})
+
+			fragments := []*v4.Packet{
+				newTestFragment(t, 1, 0, true, bytes.Repeat([]byte{0xAA}, 8)),
+				newTestFragment(t, 1, 0, true, bytes.Repeat([]byte{0xBB}, 8)),
+				newTestFragment(t, 1, 8, false, bytes.Repeat([]byte{0xDD}, 8)),
+			}
+
+			res := defragmentAll(t, defragmenter, fragments)
+			require.Equal(t, expected, res.GetPayload()[0])
+		}
+
+		assertSameOffset(t, v4.OverlapPolicyBSD, 0xAA)
+		assertSameOffset(t, v4.OverlapPolicyLinux, 0xBB)
+	})
+}
+
+func TestDefragmenterTimeout(t *testing.T) {
+	payload := fragmentsTestPayload(16)
+
+	defragmenter := v4.NewDefragmenter(v4.DefragmenterConfig{Timeout: time.Second})
+
+	_, err := defragmenter.DefragmentAt(newTestFragment(t, 1, 0, true, payload[:8]), defragmenterNow)
+	require.NoError(t, err)
+
+	res, err := defragmenter.DefragmentAt(newTestFragment(t, 1, 8, false, payload[8:]), defragmenterNow.Add(2*time.Second))
+	require.NoError(t, err)
+	require.Nil(t, res, "should not reassemble with expired fragment")
+
+	require.Equal(t, 1, defragmenter.DiscardExpired(defragmenterNow.Add(4*time.Second)), "should discard expired packet")
+	require.Equal(t, 0, defragmenter.PendingPackets(), "should not store expired packets")
+}
+
+func TestDefragmenterLimits(t *testing.T) {
+	payload := fragmentsTestPayload(32)
+
+	t.Run("fragments count", func(t *testing.T) {
+		defragmenter := v4.NewDefragmenter(v4.DefragmenterConfig{MaxFragmentsPerPacket: 2})
+
+		for i := 0; i < 2; i++ {
+			_, err := defragmenter.DefragmentAt(newTestFragment(t, 1, i*8, true, payload[i*8:(i+1)*8]), defragmenterNow)
+			require.NoError(t, err)
+		}
+
+		_, err := defragmenter.DefragmentAt(newTestFragment(t, 1, 16, true, payload[16:24]), defragmenterNow)
+		require.ErrorIs(t, err, v4.ErrFragmentsLimitExceeded, "should fail on fragments limit")
+		require.Equal(t, 0, defragmenter.PendingPackets(), "should drop packet fragments")
+	})
+
+	t.Run("memory", func(t *testing.T) {
+		defragmenter := v4.NewDefragmenter(v4.DefragmenterConfig{MaxMemory: 16})
+
+		_, err := defragmenter.DefragmentAt(newTestFragment(t, 1, 0, true, payload[:16]), defragmenterNow)
+		require.NoError(t, err)
+
+		_, err = defragmenter.DefragmentAt(newTestFragment(t, 2, 0, true, payload[:8]), defragmenterNow.Add(time.Second))
+		require.NoError(t, err, "should evict oldest packet on memory limit")
+		require.Equal(t, 1, defragmenter.PendingPackets(), "should drop oldest packet fragments")
+
+		_, err = defragmenter.DefragmentAt(newTestFragment(t, 3, 0, true, payload[:8]), defragmenterNow.Add(2*time.Second))
+		require.NoError(t, err, "should store fragment within memory limit")
+		require.Equal(t, 2, defragmenter.PendingPackets(), "should keep packets within memory limit")
+
+		// packet 2 is the oldest, so it is evicted and packet 3 completes
+		res, err := defragmenter.DefragmentAt(newTestFragment(t, 3, 8, false, payload[8:16]), defragmenterNow.Add(3*time.Second))
+		require.NoError(t, err, "should evict oldest packet on memory limit")
+		require.NotNil(t, res, "should reassemble packet after eviction")
+		require.Equal(t, 0, defragmenter.PendingPackets(), "should not keep evicted packet")
+
+		_, err = defragmenter.DefragmentAt(newTestFragment(t, 4, 0, true, payload[:24]), defragmenterNow)
+		require.ErrorIs(t, err, v4.ErrMemoryLimitExceeded, "should fail if fragment exceeds memory limit")
+		require.Equal(t, 0, defragmenter.PendingPackets(), "should not store fragment which exceeds memory limit")
+	})
+
+	t.Run("invalid fragment", func(t *testing.T) {
+		defragmenter := v4.NewDefragmenter(v4.DefragmenterConfig{})
+
+		_, err := defragmenter.DefragmentAt(newTestFragment(t, 1, 0, true, payload[:7]), defragmenterNow)
+		require.ErrorIs(t, err, v4.ErrInvalidFragment, "should fail on not aligned fragment")
+
+		_, err = defragmenter.DefragmentAt(newTestFragment(t, 1, 16, false, payload[16:24]), defragmenterNow)
+		require.NoError(t, err)
+
+		_, err = defragmenter.DefragmentAt(newTestFragment(t, 1, 16, false, payload[16:32]), defragmenterNow)
+		require.ErrorIs(t, err, v4.ErrInvalidFragment, "should fail on different last fragments")
+		require.Equal(t, 0, defragmenter.PendingPackets(), "should drop packet fragments")
+	})
+}
+
+func TestDefragmenterMalformedTotalLength(t *testing.T) {
+	payload := fragmentsTestPayload(16)
+
+	t.Run("created packet without total length", func(t *testing.T) {
+		defragmenter := v4.NewDefragmenter(v4.DefragmenterConfig{})
+
+		header := newTestFragment(t, 1, 0, true, payload[:8]).GetHeader()
+		header.TotalLength = 0
+
+		res, err := defragmenter.DefragmentAt(v4.NewPacket(header, payload[:8]), defragmenterNow)
+		require.NoError(t, err, "should use full payload if total length is not set")
+		require.Nil(t, res, "should wait more fragments")
+
+		res, err = defragmenter.DefragmentAt(newTestFragment(t, 1, 8, false, payload[8:16]), defragmenterNow)
+		require.NoError(t, err, "should reassemble packet")
+		require.Equal(t, payload, res.GetPayload(), "payload should be reassembled")
+	})
+
+	t.Run("total length less than header length", func(t *testing.T) {
+		defragmenter := v4.NewDefragmenter(v4.DefragmenterConfig{})
+
+		data, err := newTestFragment(t, 1, 0, true, payload[:8]).Serialize(netpacket.SerializeOptions{})
+		require.NoError(t, err, "should serialize fragment")
+
+		binary.BigEndian.PutUint16(data[2:4], 10)
+
+		packet, err := v4.ParsePacketWithOptions(data, netpacket.ParseOptions{Policy: netpacket.ParsePolicyLenient})
+		require.NoError(t, err, "should parse fragment with lenient policy")
+
+		_, err = defragmenter.DefragmentAt(packet, defragmenterNow)
+		require.ErrorIs(t, err, v4.ErrInvalidFragment, "should reject fragment without payload")
+		require.Equal(t, 0, defragmenter.PendingPackets(), "should not store invalid fragment")
+	})
+}
+
+func TestDefragmenterReservedFlag(t *testing.T) {
+	payload := fragmentsTestPayload(16)
+
+	first := newTestFragment(t, 1, 0, true, payload[:8])
+	first.GetHeader().SetEvil(true)
+
+	fragments := []*v4.Packet{
+		first,
+		newTestFragment(t, 1, 8, false, payload[8:16]),
+	}
+
+	res := defragmentAll(t, v4.NewDefragmenter(v4.DefragmenterConfig{}), fragments)
+	require.Equal(t, payload, res.GetPayload(), "payload should be reassembled")
+	require.True(t, res.GetHeader().IsEvil(), "should keep reserved flag from first fragment")
+}
+
+func TestDefragmenterReassembledPacketTooLong(t *testing.T) {
+	defragmenter := v4.NewDefragmenter(v4.DefragmenterConfig{})
+
+	header := newTestFragment(t, 1, 0, true, nil).GetHeader()
+	header.Options = bytes.Repeat([]byte{uint8(v4.OptionNoOperation)}, 40)
+
+	data, err := v4.NewPacket(header, fragmentsTestPayload(8)).Serialize(netpacket.SerializeOptions{
+		FixLengths:       true,
+		ComputeChecksums: true,
+	})
+	require.NoError(t, err, "should serialize first fragment")
+
+	first, err := v4.ParsePacket(data)
+	require.NoError(t, err, "should parse first fragment")
+
+	fragments := []*v4.Packet{
+		first,
+		newTestFragment(t, 1, 8, true, fragmentsTestPayload(65496)),
+	}
+
+	for _, fragment := range fragments {
+		res, err := defragmenter.DefragmentAt(fragment, defragmenterNow)
+		require.NoError(t, err, "should add fragment")
+		require.Nil(t, res, "should wait more fragments")
+	}
+
+	// last fragment fits into packet with 20 bytes header, but not with first fragment header with options
+	_, err = defragmenter.DefragmentAt(newTestFragment(t, 1, 65504, false, fragmentsTestPayload(8)), defragmenterNow)
+	require.ErrorIs(t, err, v4.ErrInvalidFragment, "should fail if reassembled packet exceeds max length")
+	require.Equal(t, 0, defragmenter.PendingPackets(), "should drop packet fragments")
+}
+
+func defragmentAll(t *testing.T, defragmenter *v4.Defragmenter, fragments []*v4.Packet) *v4.Packet {
+	t.Helper()
+
+	var res *v4.Packet
+
+	for i, fragment := range fragments {
+		var err error
+
+		res, err = defragmenter.DefragmentAt(fragment, defragmenterNow)
+		require.NoError(t, err, "should add fragment")
+
+		if i < len(fragments)-1 {
+			require.Nil(t, res, "should wait more fragments")
+		}
+	}
+
+	require.NotNil(t, res, "should reassemble packet")
+
+	return res
+}
+
+func newTestFragment(t *testing.T, id uint16, offset int, moreFragments bool, payload []byte) *v4.Packet {
+	t.Helper()
+
+	header := &v4.Header{
+		Version:        4,
+		Identification: id,
+		FragmentOffset: uint16(offset / 8),
+		TTL:            64,
+		Protocol:       uint8(v4.ProtocolUDP),
+		SourceIP:       net.IPv4(10, 0, 0, 1),
+		DestinationIP:  net.IPv4(10, 0, 0, 2),
+	}
+
+	if moreFragments {
+		header.Flags = v4.FlagMoreFragments
+	}
+
+	data, err := v4.NewPacket(header, payload).Serialize(netpacket.SerializeOptions{
+		FixLengths:       true,
+		ComputeChecksums: true,
+	})
+	require.NoError(t, err, "should serialize fragment")
+
+	packet, err := v4.ParsePacket(data)
+	require.NoError(t, err, "should parse fragment")
+
+	return packet
+}
+
+func fragmentsTestPayload(length int) []byte {
+	payload := make([]byte, length)
+	for i := range payload {
+		payload[i] = byte(i)
+	}
+
+	return payload
+}
+
+func concatBytes(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
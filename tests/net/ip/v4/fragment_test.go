@@ -0,0 +1,122 @@
+// Copyright 2026
+// license that can be found in the LICENSE file.
+
+package v4
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/name212/netpacket"
+	"github.com/name212/netpacket/net/ip/v4"
+)
+
+func TestFragment(t *testing.T) {
+	// Record Route is not copied, Router Alert is copied
+	options := []byte{
+		0x07, 0x07, 0x04, 0x00, 0x00, 0x00, 0x00,
+		0x94, 0x04, 0x00, 0x00,
+		0x00,
+	}
+
+	packet := newPacketForFragment(t, options, fragmentsTestPayload(100))
+
+	fragments, err := v4.Fragment(packet, 68)
+	require.NoError(t, err, "should fragment packet")
+	require.Len(t, fragments, 3, "should split on 3 fragments")
+
+	expected := []struct {
+		offset        uint16
+		moreFragments bool
+		payloadLen    int
+		options       []byte
+	}{
+		{offset: 0, moreFragments: true, payloadLen: 32, options: options},
+		{offset: 4, moreFragments: true, payloadLen: 40, options: []byte{0x94, 0x04, 0x00, 0x00}},
+		{offset: 9, moreFragments: false, payloadLen: 28, options: []byte{0x94, 0x04, 0x00, 0x00}},
+	}
+
+	for i, fragment := range fragments {
+		header := fragment.GetHeader()
+
+		require.LessOrEqual(t, header.GetTotalLen(), 68, "fragment %d should fit into MTU", i)
+		require.Equal(t, expected[i].offset, header.FragmentOffset, "fragment %d offset", i)
+		require.Equal(t, expected[i].moreFragments, header.MoreFragments(), "fragment %d MF flag", i)
+		require.Len(t, fragment.GetPayload(), expected[i].payloadLen, "fragment %d payload len", i)
+		require.Equal(t, expected[i].options, header.Options, "fragment %d options", i)
+		require.True(t, header.VerifyChecksum(), "fragment %d checksum should be valid", i)
+		require.Equal(t, uint16(7), header.Identification, "fragment %d identification should be kept", i)
+	}
+
+	defragmenter := v4.NewDefragmenter(v4.DefragmenterConfig{})
+	res := defragmentAll(t, defragmenter, fragments)
+	require.Equal(t, packet.GetPayload(), res.GetPayload(), "should reassemble original payload")
+	require.Equal(t, options, res.GetHeader().Options, "should keep first fragment options")
+}
+
+func TestFragmentAlreadyFragmented(t *testing.T) {
+	packet := newPacketForFragment(t, nil, fragmentsTestPayload(48))
+	packet.GetHeader().FragmentOffset = 2
+	packet.GetHeader().Flags = v4.FlagMoreFragments
+
+	fragments, err := v4.Fragment(packet, 44)
+	require.NoError(t, err, "should fragment packet")
+	require.Len(t, fragments, 2, "should split on 2 fragments")
+
+	require.Equal(t, uint16(2), fragments[0].GetHeader().FragmentOffset)
+	require.Equal(t, uint16(5), fragments[1].GetHeader().FragmentOffset)
+	require.True(t, fragments[1].GetHeader().MoreFragments(), "last fragment should keep MF flag")
+}
+
+func TestFragmentNotNeeded(t *testing.T) {
+	packet := newPacketForFragment(t, nil, fragmentsTestPayload(40))
+
+	fragments, err := v4.Fragment(packet, 60)
+	require.NoError(t, err, "should not fail")
+	require.Len(t, fragments, 1, "should not split packet")
+	require.Same(t, packet, fragments[0], "should return packet as is")
+}
+
+func TestFragmentFail(t *testing.T) {
+	t.Run("DF flag set", func(t *testing.T) {
+		packet := newPacketForFragment(t, nil, fragmentsTestPayload(100))
+		packet.GetHeader().Flags = v4.FlagDontFragment
+
+		_, err := v4.Fragment(packet, 68)
+		require.ErrorIs(t, err, v4.ErrFragmentationNeeded, "should not fragment with DF")
+
+		var fragmentationErr *v4.FragmentationNeededError
+		require.ErrorAs(t, err, &fragmentationErr, "should return typed error")
+		require.Equal(t, 68, fragmentationErr.MTU)
+		require.Equal(t, 120, fragmentationErr.PacketLength)
+		require.Same(t, packet.GetHeader(), fragmentationErr.Header)
+	})
+
+	t.Run("MTU too small", func(t *testing.T) {
+		packet := newPacketForFragment(t, nil, fragmentsTestPayload(100))
+
+		_, err := v4.Fragment(packet, 27)
+		require.ErrorIs(t, err, netpacket.ErrCannotSerialize, "should not fragment with small MTU")
+	})
+}
+
+func newPacketForFragment(t *testing.T, options []byte, payload []byte) *v4.Packet {
+	t.Helper()
+
+	header := validHeaderForSerialize()
+	header.Flags = 0
+	header.Identification = 7
+	header.Options = options
+
+	data, err := v4.NewPacket(header, payload).Serialize(netpacket.SerializeOptions{
+		FixLengths:       true,
+		ComputeChecksums: true,
+	})
+	require.NoError(t, err, "should serialize packet")
+
+	packet, err := v4.ParsePacket(data)
+	require.NoError(t, err, "should parse packet")
+
+	return packet
+}
@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"testing"
 
+	"github.com/name212/netpacket"
 	"github.com/name212/netpacket/net/ip/v4"
 	"github.com/name212/netpacket/tests"
 	"github.com/stretchr/testify/require"
@@ -38,10 +39,10 @@ func TestParseIPv4Header(t *testing.T) {
 	require.Equal(t, 64, header.GetTTL(), "TTL should be 64")
 	require.Equal(t, uint16(45542), header.Checksum, "checksum should be 45542")
 
-	flags := header.GetFlags()
-	require.False(t, flags.IsEvil, "flags should not be evil")
-	require.False(t, flags.MoreFragments, "flags should not be more fragments")
-	require.True(t, flags.DontFragment, "flags should be dont fragment")
+	require.Equal(t, v4.FlagDontFragment, header.GetFlags(), "flags should be only dont fragment")
+	require.False(t, header.IsEvil(), "flags should not be evil")
+	require.False(t, header.MoreFragments(), "flags should not be more fragments")
+	require.True(t, header.DontFragment(), "flags should be dont fragment")
 
 	assertNoOptions(t, header)
 
@@ -52,9 +53,12 @@ Source: 192.168.0.104
 Destination: 192.168.0.1
 Protocol: TCP
 TTL: 64
+DSCP: CS0
+ECN: Not-ECT
 Header Size: 20
 Packet Size: 60
 Flags:
+	Reserved: false
 	Don't Fragment: true
 	More Fragments: false
 No options set
@@ -103,20 +107,29 @@ Source: 175.45.176.0
 Destination: 149.171.126.11
 Protocol: ICMP
 TTL: 254
+DSCP: CS0
+ECN: Not-ECT
 Header Size: 36
 Packet Size: 40
 Flags:
+	Reserved: false
 	Don't Fragment: false
 	More Fragments: false
 Options:
 	Option:
 		Type: NOP(1)
 		Type description: No Operation
+		Copied: false
+		Class: control(0)
+		Number: 1
 		Full Length: 1
 		No data
 	Option:
 		Type: SEC(130)
 		Type description: Security RIPSO
+		Copied: true
+		Class: control(0)
+		Number: 2
 		Full Length: 11
 		Hex data:
 			0x00 0x00 0x00 0x00 0x00 0x00 0x00 0x00
@@ -129,6 +142,9 @@ Checksum: 49632
 Option:
 	Type: NOP(1)
 	Type description: No Operation
+	Copied: false
+	Class: control(0)
+	Number: 1
 	Full Length: 1
 	No data
 `
@@ -138,6 +154,9 @@ Option:
 Option:
 	Type: SEC(130)
 	Type description: Security RIPSO
+	Copied: true
+	Class: control(0)
+	Number: 2
 	Full Length: 11
 	Hex data:
 		0x00 0x00 0x00 0x00 0x00 0x00 0x00 0x00
@@ -165,3 +184,217 @@ func parseHeader(t *testing.T, data []byte, totalLen int) *v4.Header {
 
 	return header
 }
+
+func TestIPv4HeaderDSCPAndECN(t *testing.T) {
+	header := validHeaderForSerialize()
+	header.ToS = 0xB9
+
+	require.Equal(t, v4.DSCPEF, header.GetDSCP(), "DSCP should be EF")
+	require.Equal(t, v4.ECNECT1, header.GetECN(), "ECN should be ECT(1)")
+	require.Equal(t, "EF", header.GetDSCP().String())
+	require.Equal(t, "ECT(1)", header.GetECN().String())
+
+	header.SetDSCP(v4.DSCPAF41)
+	require.Equal(t, uint8(0x89), header.ToS, "should keep ECN bits")
+
+	header.SetECN(v4.ECNCE)
+	require.Equal(t, uint8(0x8B), header.ToS, "should keep DSCP bits")
+	require.Equal(t, v4.DSCPAF41, header.GetDSCP())
+	require.Equal(t, v4.ECNCE, header.GetECN())
+
+	header.SetDSCP(v4.DSCP(0xFF))
+	require.Equal(t, v4.DSCP(63), header.GetDSCP(), "should use only 6 bits of DSCP")
+
+	require.Equal(t, "LE", v4.DSCPLE.String())
+	require.Equal(t, "VA", v4.DSCPVA.String())
+	require.Equal(t, "CS7", v4.DSCPCS7.String())
+	require.Equal(t, "5", v4.DSCP(5).String(), "unnamed code point should be number")
+	require.Equal(t, "ECT(0)", v4.ECNECT0.String())
+	require.Equal(t, "Not-ECT", v4.ECNNotECT.String())
+
+	header.SetDSCP(v4.DSCPCS1)
+	header.SetECN(v4.ECNECT0)
+	require.NoError(t, header.FixLengths(0))
+
+	data, err := header.MarshalBinary()
+	require.NoError(t, err)
+
+	parsed, err := v4.ParseHeader(data)
+	require.NoError(t, err)
+	require.Equal(t, v4.DSCPCS1, parsed.GetDSCP(), "DSCP should be serialized")
+	require.Equal(t, v4.ECNECT0, parsed.GetECN(), "ECN should be serialized")
+	require.Contains(t, parsed.String(), "DSCP: CS1\nECN: ECT(0)\n")
+}
+
+func TestIPv4HeaderFlags(t *testing.T) {
+	require.Equal(t, "none", v4.Flags(0).String())
+	require.Equal(t, "RESERVED DF MF", v4.Flags(0x07).String())
+
+	flags := v4.Flags(0).Set(v4.FlagDontFragment).Set(v4.FlagMoreFragments)
+	require.True(t, flags.Has(v4.FlagDontFragment), "should be DF")
+	require.True(t, flags.Has(v4.FlagMoreFragments), "should be MF")
+	require.False(t, flags.Has(v4.FlagReserved), "should not be reserved")
+	require.Equal(t, "DF MF", flags.String())
+
+	flags = flags.Clear(v4.FlagDontFragment)
+	require.Equal(t, v4.FlagMoreFragments, flags)
+	require.Equal(t, v4.FlagReserved|v4.FlagMoreFragments, flags.With(v4.FlagReserved, true))
+	require.Equal(t, v4.Flags(0), flags.With(v4.FlagMoreFragments, false))
+
+	// bits beyond 3-bit field are dropped by Set and Clear the same way
+	require.Equal(t, v4.FlagDontFragment|v4.FlagMoreFragments, v4.Flags(0xF1).Set(v4.FlagDontFragment))
+	require.Equal(t, v4.FlagMoreFragments, v4.Flags(0xF3).Clear(v4.FlagDontFragment))
+
+	header := validHeaderForSerialize()
+	header.Flags = 0
+
+	header.SetDontFragment(true)
+	header.SetMoreFragments(true)
+	require.Equal(t, v4.FlagDontFragment|v4.FlagMoreFragments, header.Flags, "raw flags should be in sync")
+	require.True(t, header.DontFragment())
+	require.True(t, header.MoreFragments())
+
+	header.SetDontFragment(false)
+	require.Equal(t, v4.FlagMoreFragments, header.GetFlags(), "should clear only DF")
+
+	header.Flags = v4.FlagDontFragment
+	require.True(t, header.DontFragment(), "accessors should follow raw flags")
+	require.False(t, header.MoreFragments(), "accessors should follow raw flags")
+
+	header.SetMoreFragments(true)
+	header.FragmentOffset = 0x1FFF
+	require.NoError(t, header.FixLengths(0))
+
+	data, err := header.MarshalBinary()
+	require.NoError(t, err)
+
+	parsed, err := v4.ParseHeader(data)
+	require.NoError(t, err)
+	require.Equal(t, v4.FlagDontFragment|v4.FlagMoreFragments, parsed.GetFlags(), "DF and MF should be parsed together")
+	require.Equal(t, uint16(0x1FFF), parsed.FragmentOffset, "flags should not affect fragment offset")
+}
+
+func TestParseIPv4HeaderReservedFlag(t *testing.T) {
+	header := validHeaderForSerialize()
+	header.SetEvil(true)
+	require.NoError(t, header.FixLengths(0))
+
+	data, err := header.MarshalBinary()
+	require.NoError(t, err)
+
+	_, err = v4.ParseHeader(data)
+	require.Error(t, err, "strict policy should reject reserved flag")
+
+	_, err = v4.ParsePacket(data)
+	require.Error(t, err, "strict policy should reject reserved flag")
+
+	lenient := netpacket.ParseOptions{Policy: netpacket.ParsePolicyLenient}
+
+	parsed, err := v4.ParseHeaderWithOptions(data, lenient)
+	require.NoError(t, err, "lenient policy should accept reserved flag")
+	require.True(t, parsed.IsEvil(), "reserved flag should be reported")
+	require.Contains(t, parsed.String(), "Reserved: true\n")
+
+	packet, err := v4.ParsePacketWithOptions(data, lenient)
+	require.NoError(t, err, "lenient policy should accept reserved flag")
+	require.True(t, packet.GetHeader().IsEvil(), "reserved flag should be reported")
+}
+
+func TestParseIPv4HeaderParseErrors(t *testing.T) {
+	validHeader := func(t *testing.T) []byte {
+		t.Helper()
+
+		header := validHeaderForSerialize()
+		require.NoError(t, header.FixLengths(0))
+
+		data, err := header.MarshalBinary()
+		require.NoError(t, err)
+
+		return data
+	}
+
+	t.Run("short data", func(t *testing.T) {
+		_, err := v4.ParseHeader(validHeader(t)[:10])
+
+		tests.AssertParseError(t, err, netpacket.ErrShortData, netpacket.ParseError{
+			Kind:     v4.Kind,
+			Field:    "data length",
+			Offset:   0,
+			Expected: ">= 20",
+			Actual:   10,
+		})
+	})
+
+	t.Run("bad version", func(t *testing.T) {
+		data := validHeader(t)
+		data[0] = 0x65
+
+		_, err := v4.ParseHeader(data)
+
+		tests.AssertParseError(t, err, netpacket.ErrCannotParseHeader, netpacket.ParseError{
+			Kind:     v4.Kind,
+			Field:    "version",
+			Offset:   0,
+			Expected: "4",
+			Actual:   6,
+		})
+		require.EqualError(t, err, "cannot parse header: IPv4 at offset 0: version expected 4, actual 6")
+
+		_, err = v4.ParsePacket(data)
+		require.EqualError(t, err, "cannot parse header: IPv4 at offset 0: version expected 4, actual 6", "should not wrap twice")
+	})
+
+	t.Run("header length too small", func(t *testing.T) {
+		data := validHeader(t)
+		data[0] = 0x44
+
+		_, err := v4.ParseHeader(data)
+
+		tests.AssertParseError(t, err, netpacket.ErrCannotParseHeader, netpacket.ParseError{
+			Kind:     v4.Kind,
+			Field:    "header length",
+			Offset:   0,
+			Expected: ">= 20",
+			Actual:   16,
+		})
+	})
+
+	t.Run("reserved flag", func(t *testing.T) {
+		data := validHeader(t)
+		data[6] |= 0x80
+
+		_, err := v4.ParseHeader(data)
+
+		tests.AssertParseError(t, err, netpacket.ErrCannotParseHeader, netpacket.ParseError{
+			Kind:     v4.Kind,
+			Field:    "reserved flag",
+			Offset:   6,
+			Expected: "0",
+			Actual:   1,
+		})
+	})
+
+	t.Run("malformed options", func(t *testing.T) {
+		header := &v4.Header{Options: []byte{0x01, 0x07, 0x0b, 0x04}}
+		_, err := header.ParseOptions()
+
+		tests.AssertParseError(t, err, netpacket.ErrShortData, netpacket.ParseError{
+			Kind:     v4.Kind,
+			Field:    "option ROR(7) length",
+			Offset:   22,
+			Expected: "<= remaining header length 3",
+			Actual:   11,
+		})
+
+		header.Options = []byte{0x44, 0x02, 0x00, 0x00}
+		_, err = header.ParseOptions()
+
+		tests.AssertParseError(t, err, netpacket.ErrCannotParseHeader, netpacket.ParseError{
+			Kind:     v4.Kind,
+			Field:    "option TS(68) length",
+			Offset:   21,
+			Expected: "> 2",
+			Actual:   2,
+		})
+	})
+}
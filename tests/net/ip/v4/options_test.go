@@ -0,0 +1,942 @@
+// Copyright 2026
+// license that can be found in the LICENSE file.
+
+package v4
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/name212/netpacket"
+	"github.com/name212/netpacket/net/ip/v4"
+	"github.com/name212/netpacket/tests"
+)
+
+func TestRouteOption(t *testing.T) {
+	options := parseOptionsFromHeader(t, []byte{
+		0x83, 0x0b, 0x08,
+		0x0a, 0x00, 0x00, 0x01,
+		0x0a, 0x00, 0x00, 0x02,
+		0x00,
+	})
+	require.Len(t, options, 1, "should parse one option")
+
+	option := options[0]
+	assertOptionTypeAndLength(t, option, v4.OptionLooseSourceRoute, "LSR", 11)
+	require.True(t, option.IsRouteOption(), "LSR should be route option")
+
+	route, err := option.Route()
+	require.NoError(t, err, "should decode route")
+	require.Equal(t, uint8(8), route.Pointer)
+	require.Equal(t, []net.IP{net.IPv4(10, 0, 0, 1).To4(), net.IPv4(10, 0, 0, 2).To4()}, route.Addresses)
+	require.Equal(t, 1, route.NextIndex(), "next slot should be second address")
+	require.False(t, route.IsFull(), "route should not be full")
+
+	expectedString := `
+Option:
+	Type: LSR(131)
+	Type description: Loose Source Route
+	Copied: true
+	Class: control(0)
+	Number: 3
+	Full Length: 11
+	Pointer: 8
+	Next slot: 1
+	Addresses:
+		0: 10.0.0.1
+		1: 10.0.0.2
+`
+	tests.AssertStringer(t, &option, expectedString)
+}
+
+func TestRouteOptionFull(t *testing.T) {
+	options := parseOptionsFromHeader(t, []byte{
+		0x07, 0x07, 0x08,
+		0xc0, 0xa8, 0x00, 0x01,
+		0x00,
+	})
+
+	route, err := options[0].Route()
+	require.NoError(t, err, "should decode record route")
+	require.Equal(t, -1, route.NextIndex(), "should not have next slot")
+	require.True(t, route.IsFull(), "route should be full")
+
+	expectedString := `
+Option:
+	Type: ROR(7)
+	Type description: Record Route
+	Copied: false
+	Class: control(0)
+	Number: 7
+	Full Length: 7
+	Pointer: 8
+	Next slot: route is full
+	Addresses:
+		0: 192.168.0.1
+`
+	tests.AssertStringer(t, &options[0], expectedString)
+}
+
+func TestRouteOptionInvalid(t *testing.T) {
+	t.Run("wrong type", func(t *testing.T) {
+		options := parseOptionsFromHeader(t, []byte{0x94, 0x04, 0x00, 0x00})
+
+		_, err := options[0].Route()
+		require.ErrorIs(t, err, v4.ErrWrongOptionType, "should not decode router alert as route")
+	})
+
+	t.Run("invalid pointer", func(t *testing.T) {
+		options := parseOptionsFromHeader(t, []byte{0x89, 0x07, 0x03, 0x0a, 0x00, 0x00, 0x01, 0x00})
+
+		_, err := options[0].Route()
+		assertOptionParseError(t, err, "option SSR(137) pointer", 22, ">= 4", 3)
+	})
+
+	t.Run("misaligned pointer", func(t *testing.T) {
+		options := parseOptionsFromHeader(t, []byte{0x83, 0x0b, 0x06, 0x0a, 0x00, 0x00, 0x01, 0x0a, 0x00, 0x00, 0x02, 0x00})
+
+		_, err := options[0].Route()
+		assertOptionParseError(t, err, "option LSR(131) pointer", 22, "4 + 4*n", 6)
+
+		route := v4.RouteOption{Pointer: 6, Addresses: make([]net.IP, 2)}
+		require.Equal(t, -1, route.NextIndex(), "should not have next slot with misaligned pointer")
+	})
+
+	t.Run("pointer exceeds length", func(t *testing.T) {
+		options := parseOptionsFromHeader(t, []byte{0x07, 0x07, 0x0c, 0xc0, 0xa8, 0x00, 0x01, 0x00})
+
+		_, err := options[0].Route()
+		assertOptionParseError(t, err, "option ROR(7) pointer", 22, "<= 8", 12)
+	})
+
+	t.Run("invalid addresses length", func(t *testing.T) {
+		options := parseOptionsFromHeader(t, []byte{0x07, 0x06, 0x04, 0x0a, 0x00, 0x00, 0x00, 0x00})
+
+		_, err := options[0].Route()
+		assertOptionParseError(t, err, "option ROR(7) length", 21, "3 + 4*n", 6)
+	})
+
+	t.Run("offset of second option", func(t *testing.T) {
+		options := parseOptionsFromHeader(t, []byte{0x01, 0x89, 0x07, 0x03, 0x0a, 0x00, 0x00, 0x01})
+
+		_, err := options[1].Route()
+		assertOptionParseError(t, err, "option SSR(137) pointer", 23, ">= 4", 3)
+	})
+}
+
+func assertOptionParseError(t *testing.T, err error, field string, offset int, expected string, actual int) {
+	t.Helper()
+
+	tests.AssertParseError(t, err, netpacket.ErrCannotParseHeader, netpacket.ParseError{
+		Kind:     v4.Kind,
+		Field:    field,
+		Offset:   offset,
+		Expected: expected,
+		Actual:   actual,
+	})
+}
+
+func parseOptionsFromHeader(t *testing.T, optionsData []byte) []v4.Option {
+	t.Helper()
+
+	header := validHeaderForSerialize()
+	header.Options = optionsData
+	require.NoError(t, header.FixLengths(0), "should fix lengths")
+
+	data, err := header.MarshalBinary()
+	require.NoError(t, err, "should marshal header")
+
+	parsed, err := v4.ParseHeader(data)
+	require.NoError(t, err, "should parse header")
+
+	options, err := parsed.ParseOptions()
+	require.NoError(t, err, "should parse options")
+
+	return options
+}
+
+func TestTimestampOptionOnlyTimestamps(t *testing.T) {
+	optionsData := []byte{
+		0x44, 0x0c, 0x09, 0x10,
+		0x00, 0x00, 0x00, 0x64,
+		0x00, 0x00, 0x00, 0x00,
+	}
+
+	options := parseOptionsFromHeader(t, optionsData)
+	require.Len(t, options, 1, "should parse one option")
+
+	ts, err := options[0].Timestamp()
+	require.NoError(t, err, "should decode timestamp")
+	require.Equal(t, uint8(9), ts.Pointer)
+	require.Equal(t, uint8(1), ts.Overflow)
+	require.Equal(t, v4.TimestampOnly, ts.Flag)
+	require.Len(t, ts.Entries, 2, "should contain all slots")
+	require.Equal(t, 1, ts.NextIndex(), "next slot should be second")
+	require.Equal(t, []v4.TimestampEntry{{Timestamp: 100}}, ts.Recorded())
+
+	header := validHeaderForSerialize()
+	header.Flags = 0
+	header.Options = optionsData
+	require.NoError(t, header.FixLengths(0))
+	header.Checksum = header.ComputeChecksum()
+
+	expectedHeaderString := `
+Source: 10.0.0.1
+Destination: 10.0.0.2
+Protocol: UDP
+TTL: 64
+DSCP: CS0
+ECN: Not-ECT
+Header Size: 32
+Packet Size: 32
+Flags:
+	Reserved: false
+	Don't Fragment: false
+	More Fragments: false
+Options:
+	Option:
+		Type: TS(68)
+		Type description: Timestamp
+		Copied: false
+		Class: debugging and measurement(2)
+		Number: 4
+		Full Length: 12
+		Pointer: 9
+		Overflow: 1
+		Flag: timestamps only(0)
+		Timestamps:
+			0: 100
+Checksum: 5707
+`
+	tests.AssertStringer(t, header, expectedHeaderString)
+}
+
+func TestTimestampOptionWithAddresses(t *testing.T) {
+	options := parseOptionsFromHeader(t, []byte{
+		0x44, 0x14, 0x15, 0x01,
+		0x0a, 0x00, 0x00, 0x01,
+		0x80, 0x00, 0x00, 0x01,
+		0x0a, 0x00, 0x00, 0x02,
+		0x00, 0x00, 0x00, 0xc8,
+	})
+
+	ts, err := options[0].Timestamp()
+	require.NoError(t, err, "should decode timestamp")
+	require.Equal(t, v4.TimestampWithAddress, ts.Flag)
+	require.True(t, ts.IsFull(), "option should be full")
+	require.Len(t, ts.Recorded(), 2, "all entries should be recorded")
+	require.False(t, ts.Entries[0].IsStandard(), "first timestamp should be non-standard")
+	require.True(t, ts.Entries[1].IsStandard(), "second timestamp should be standard")
+
+	expectedString := `
+Option:
+	Type: TS(68)
+	Type description: Timestamp
+	Copied: false
+	Class: debugging and measurement(2)
+	Number: 4
+	Full Length: 20
+	Pointer: 21
+	Overflow: 0
+	Flag: address and timestamp(1)
+	Timestamps:
+		0: 10.0.0.1 2147483649 (non-standard)
+		1: 10.0.0.2 200
+`
+	tests.AssertStringer(t, &options[0], expectedString)
+}
+
+func TestTimestampOptionPrespecified(t *testing.T) {
+	options := parseOptionsFromHeader(t, []byte{
+		0x44, 0x0c, 0x05, 0x03,
+		0x0a, 0x00, 0x00, 0x01,
+		0x00, 0x00, 0x00, 0x00,
+	})
+
+	ts, err := options[0].Timestamp()
+	require.NoError(t, err, "should decode timestamp")
+	require.Equal(t, v4.TimestampPrespecified, ts.Flag)
+	require.Equal(t, net.IPv4(10, 0, 0, 1).To4(), ts.Entries[0].Address, "address should be prespecified")
+	require.Empty(t, ts.Recorded(), "should not contain recorded timestamps")
+
+	expectedString := `
+Option:
+	Type: TS(68)
+	Type description: Timestamp
+	Copied: false
+	Class: debugging and measurement(2)
+	Number: 4
+	Full Length: 12
+	Pointer: 5
+	Overflow: 0
+	Flag: prespecified addresses(3)
+	No timestamps recorded
+`
+	tests.AssertStringer(t, &options[0], expectedString)
+}
+
+func TestTimestampOptionInvalid(t *testing.T) {
+	testCases := []struct {
+		name     string
+		data     []byte
+		field    string
+		offset   int
+		expected string
+		actual   int
+	}{
+		{
+			name:     "unsupported flag",
+			data:     []byte{0x44, 0x08, 0x05, 0x02, 0x00, 0x00, 0x00, 0x00},
+			field:    "option TS(68) flag",
+			offset:   23,
+			expected: "0, 1 or 3",
+			actual:   2,
+		},
+		{
+			name:     "pointer not on entry start",
+			data:     []byte{0x44, 0x0c, 0x07, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+			field:    "option TS(68) pointer",
+			offset:   22,
+			expected: "5 + 8*n",
+			actual:   7,
+		},
+		{
+			name:     "pointer exceeds length",
+			data:     []byte{0x44, 0x08, 0x0d, 0x00, 0x00, 0x00, 0x00, 0x00},
+			field:    "option TS(68) pointer",
+			offset:   22,
+			expected: "<= 9",
+			actual:   13,
+		},
+		{
+			name:     "entries length",
+			data:     []byte{0x44, 0x0a, 0x05, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+			field:    "option TS(68) length",
+			offset:   21,
+			expected: "4 + 8*n for flag 1",
+			actual:   10,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			options := parseOptionsFromHeader(t, tc.data)
+
+			_, err := options[0].Timestamp()
+			assertOptionParseError(t, err, tc.field, tc.offset, tc.expected, tc.actual)
+		})
+	}
+
+	options := parseOptionsFromHeader(t, []byte{0x94, 0x04, 0x00, 0x00})
+	_, err := options[0].Timestamp()
+	require.ErrorIs(t, err, v4.ErrWrongOptionType, "should not decode router alert as timestamp")
+}
+
+func TestCIPSOOption(t *testing.T) {
+	options := parseOptionsFromHeader(t, []byte{
+		0x86, 0x1e,
+		0x00, 0x00, 0x00, 0x03,
+		0x01, 0x06, 0x00, 0x02, 0x80, 0x40,
+		0x02, 0x08, 0x00, 0x03, 0x00, 0x05, 0x01, 0x00,
+		0x05, 0x0a, 0x00, 0x01, 0x00, 0x14, 0x00, 0x0a, 0x00, 0x05,
+		0x00, 0x00,
+	})
+	require.Len(t, options, 1, "should parse one option")
+
+	cipso, err := options[0].CIPSO()
+	require.NoError(t, err, "should decode CIPSO")
+
+	expected := &v4.CIPSOOption{
+		DOI: 3,
+		Tags: []v4.CIPSOTag{
+			{Type: v4.CIPSOTagRestrictedBitmap, Level: 2, Categories: []uint16{0, 9}},
+			{Type: v4.CIPSOTagEnumerated, Level: 3, Categories: []uint16{5, 256}},
+			{Type: v4.CIPSOTagRanged, Level: 1, Ranges: []v4.CIPSOCategoryRange{{High: 20, Low: 10}, {High: 5}}},
+		},
+	}
+	require.Equal(t, expected, cipso)
+
+	expectedString := `
+Option:
+	Type: CIPSO(134)
+	Type description: Commercial IP Security Option
+	Copied: true
+	Class: control(0)
+	Number: 6
+	Full Length: 30
+	DOI: 3
+	Tags:
+		Tag: restricted bitmap(1)
+			Level: 2
+			Categories: 0 9
+		Tag: enumerated(2)
+			Level: 3
+			Categories: 5 256
+		Tag: ranged(5)
+			Level: 1
+			Ranges: 10-20 0-5
+`
+	tests.AssertStringer(t, &options[0], expectedString)
+}
+
+func TestCIPSOOptionEncode(t *testing.T) {
+	cipso := v4.CIPSOOption{
+		DOI: 16,
+		Tags: []v4.CIPSOTag{
+			{Type: v4.CIPSOTagPermissiveBitmap, Level: 7, Categories: []uint16{1, 15, 16}},
+			{Type: v4.CIPSOTagRanged, Level: 1, Ranges: []v4.CIPSOCategoryRange{{High: 9, Low: 3}}},
+			{Type: v4.CIPSOTagFreeForm, Data: []byte{0xAA, 0xBB}},
+		},
+	}
+
+	option, err := v4.NewCIPSOOption(cipso)
+	require.NoError(t, err, "should encode CIPSO")
+
+	data := option.AppendTo(nil)
+	require.Equal(t, []byte{
+		0x86, 0x19,
+		0x00, 0x00, 0x00, 0x10,
+		0x06, 0x07, 0x00, 0x07, 0x40, 0x01, 0x80,
+		0x05, 0x08, 0x00, 0x01, 0x00, 0x09, 0x00, 0x03,
+		0x07, 0x04, 0xAA, 0xBB,
+	}, data)
+
+	options := parseOptionsFromHeader(t, append(data, 0x00, 0x00, 0x00))
+	decoded, err := options[0].CIPSO()
+	require.NoError(t, err, "should decode encoded CIPSO")
+	require.Equal(t, &cipso, decoded)
+
+	_, err = v4.NewCIPSOOption(v4.CIPSOOption{
+		Tags: []v4.CIPSOTag{{Type: v4.CIPSOTagRestrictedBitmap, Categories: []uint16{240}}},
+	})
+	require.Error(t, err, "should not encode category out of bitmap")
+	require.Contains(t, err.Error(), "option CIPSO(134): tag 1 category 240 exceeds 239")
+}
+
+func TestCIPSOOptionInvalid(t *testing.T) {
+	testCases := []struct {
+		name     string
+		data     []byte
+		field    string
+		offset   int
+		expected string
+		actual   int
+	}{
+		{
+			name:     "no DOI",
+			data:     []byte{0x86, 0x04, 0x00, 0x00},
+			field:    "option CIPSO(134) length",
+			offset:   21,
+			expected: ">= 6",
+			actual:   4,
+		},
+		{
+			name:     "tag length exceeds option",
+			data:     []byte{0x86, 0x08, 0x00, 0x00, 0x00, 0x01, 0x01, 0x06},
+			field:    "option CIPSO(134) tag 1 length",
+			offset:   27,
+			expected: "from 2 to remaining option length 2",
+			actual:   6,
+		},
+		{
+			name:     "short level tag",
+			data:     []byte{0x86, 0x09, 0x00, 0x00, 0x00, 0x01, 0x01, 0x03, 0x00, 0x00, 0x00, 0x00},
+			field:    "option CIPSO(134) tag 1 length",
+			offset:   27,
+			expected: "from 4 to 34",
+			actual:   3,
+		},
+		{
+			name:     "enumerated tag categories not aligned",
+			data:     []byte{0x86, 0x0b, 0x00, 0x00, 0x00, 0x01, 0x02, 0x05, 0x00, 0x00, 0x00, 0x00},
+			field:    "option CIPSO(134) tag 2 length",
+			offset:   27,
+			expected: "4 + 2*n",
+			actual:   5,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			options := parseOptionsFromHeader(t, tc.data)
+
+			_, err := options[0].CIPSO()
+			assertOptionParseError(t, err, tc.field, tc.offset, tc.expected, tc.actual)
+		})
+	}
+}
+
+func TestRIPSOOption(t *testing.T) {
+	options := parseOptionsFromHeader(t, []byte{
+		0x82, 0x05, 0x3d, 0xa1, 0x10,
+		0x85, 0x05, 0x01, 0xaa, 0xbb,
+		0x00, 0x00,
+	})
+	require.Len(t, options, 2, "should parse two options")
+
+	ripso, err := options[0].RIPSO()
+	require.NoError(t, err, "should decode RIPSO")
+	require.Equal(t, v4.RIPSOTopSecret, ripso.Classification)
+	require.Equal(t, []v4.ProtectionAuthority{0xa0, 0x10}, ripso.Authorities)
+	require.True(t, ripso.Authorities[0].Has(v4.ProtectionAuthorityGENSER), "should have GENSER")
+	require.True(t, ripso.Authorities[0].Has(v4.ProtectionAuthoritySCI), "should have SCI")
+	require.False(t, ripso.Authorities[0].Has(v4.ProtectionAuthorityNSA), "should not have NSA")
+
+	expectedString := `
+Option:
+	Type: SEC(130)
+	Type description: Security RIPSO
+	Copied: true
+	Class: control(0)
+	Number: 2
+	Full Length: 5
+	Classification: Top Secret(0x3d)
+	Protection authorities: GENSER SCI 0x10
+`
+	tests.AssertStringer(t, &options[0], expectedString)
+
+	extended, err := options[1].ExtendedSecurity()
+	require.NoError(t, err, "should decode extended security")
+	require.Equal(t, &v4.ExtendedSecurityOption{FormatCode: 1, Info: []byte{0xaa, 0xbb}}, extended)
+
+	expectedString = `
+Option:
+	Type: E-SEC(133)
+	Type description: Extended Security (RIPSO)
+	Copied: true
+	Class: control(0)
+	Number: 5
+	Full Length: 5
+	Format code: 1
+	Additional security info:
+		0xAA 0xBB
+`
+	tests.AssertStringer(t, &options[1], expectedString)
+
+	_, err = options[0].ExtendedSecurity()
+	require.ErrorIs(t, err, v4.ErrWrongOptionType, "should not decode RIPSO as extended security")
+}
+
+func TestRIPSOOptionEncode(t *testing.T) {
+	ripso := v4.RIPSOOption{
+		Classification: v4.RIPSOSecret,
+		Authorities: []v4.ProtectionAuthority{
+			v4.ProtectionAuthorityNSA | v4.ProtectionAuthorityDOE,
+			0x02,
+		},
+	}
+
+	option, err := v4.NewRIPSOOption(ripso)
+	require.NoError(t, err, "should encode RIPSO")
+	require.Equal(t, []byte{0x82, 0x05, 0x5a, 0x19, 0x02}, option.AppendTo(nil))
+
+	_, err = v4.NewRIPSOOption(v4.RIPSOOption{Classification: 0x02})
+	require.Error(t, err, "should not encode unknown classification")
+
+	extended, err := v4.NewExtendedSecurityOption(v4.ExtendedSecurityOption{FormatCode: 2, Info: []byte{0x01}})
+	require.NoError(t, err, "should encode extended security")
+	require.Equal(t, []byte{0x85, 0x04, 0x02, 0x01}, extended.AppendTo(nil))
+}
+
+func TestRIPSOOptionInvalid(t *testing.T) {
+	testCases := []struct {
+		name     string
+		data     []byte
+		field    string
+		offset   int
+		expected string
+		actual   int
+	}{
+		{
+			name:     "unknown classification",
+			data:     []byte{0x82, 0x03, 0x02, 0x00},
+			field:    "option SEC(130) classification level",
+			offset:   22,
+			expected: "known classification level",
+			actual:   0x02,
+		},
+		{
+			name:     "last octet has termination bit",
+			data:     []byte{0x82, 0x04, 0xab, 0x81},
+			field:    "option SEC(130) protection authority",
+			offset:   23,
+			expected: "last octet without termination bit",
+			actual:   0x81,
+		},
+		{
+			name:     "middle octet without termination bit",
+			data:     []byte{0x82, 0x05, 0xab, 0x80, 0x10, 0x00, 0x00, 0x00},
+			field:    "option SEC(130) protection authority",
+			offset:   23,
+			expected: "octet with termination bit",
+			actual:   0x80,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			options := parseOptionsFromHeader(t, tc.data)
+
+			_, err := options[0].RIPSO()
+			assertOptionParseError(t, err, tc.field, tc.offset, tc.expected, tc.actual)
+		})
+	}
+}
+
+func TestFixedLayoutOptions(t *testing.T) {
+	options := parseOptionsFromHeader(t, []byte{
+		0x94, 0x04, 0x00, 0x00,
+		0x19, 0x08, 0x03, 0x40, 0x00, 0x00, 0x01, 0x04,
+		0x88, 0x04, 0x12, 0x34,
+		0x0b, 0x04, 0x05, 0xdc,
+		0x52, 0x0c, 0x00, 0x2a, 0x00, 0x03, 0xff, 0xff, 0xc0, 0xa8, 0x00, 0x01,
+		0x0c, 0x04, 0x05, 0x78,
+	})
+	require.Len(t, options, 6, "should parse all options")
+
+	routerAlert, err := options[0].RouterAlert()
+	require.NoError(t, err, "should decode router alert")
+	require.Equal(t, v4.RouterAlertExaminePacket, routerAlert)
+
+	qs, err := options[1].QuickStart()
+	require.NoError(t, err, "should decode quick start")
+	require.Equal(t, &v4.QuickStartOption{
+		Function: v4.QuickStartRateRequest,
+		Rate:     3,
+		TTL:      64,
+		Nonce:    65,
+	}, qs)
+	require.Equal(t, uint64(320), qs.RateKbps())
+
+	streamID, err := options[2].StreamID()
+	require.NoError(t, err, "should decode stream ID")
+	require.Equal(t, uint16(0x1234), streamID)
+
+	mtu, err := options[3].MTU()
+	require.NoError(t, err, "should decode MTU probe")
+	require.Equal(t, uint16(1500), mtu)
+
+	traceroute, err := options[4].Traceroute()
+	require.NoError(t, err, "should decode traceroute")
+	require.Equal(t, &v4.TracerouteOption{
+		ID:           42,
+		OutboundHops: 3,
+		ReturnHops:   0xffff,
+		Originator:   net.IPv4(192, 168, 0, 1).To4(),
+	}, traceroute)
+
+	mtu, err = options[5].MTU()
+	require.NoError(t, err, "should decode MTU reply")
+	require.Equal(t, uint16(1400), mtu)
+
+	expectedStrings := []string{
+		`
+Option:
+	Type: RTRALT(148)
+	Type description: Router Alert
+	Copied: true
+	Class: control(0)
+	Number: 20
+	Full Length: 4
+	Value: 0 (router shall examine packet)
+`,
+		`
+Option:
+	Type: QS(25)
+	Type description: Quick Start
+	Copied: false
+	Class: control(0)
+	Number: 25
+	Full Length: 8
+	Function: rate request(0)
+	Rate: 3 (320 Kbps)
+	QS TTL: 64
+	QS nonce: 65
+`,
+		`
+Option:
+	Type: SID(136)
+	Type description: Stream ID
+	Copied: true
+	Class: control(0)
+	Number: 8
+	Full Length: 4
+	Stream ID: 4660
+`,
+		`
+Option:
+	Type: MTUP(11)
+	Type description: MTU Probe
+	Copied: false
+	Class: control(0)
+	Number: 11
+	Full Length: 4
+	MTU: 1500
+`,
+		`
+Option:
+	Type: TR(82)
+	Type description: Traceroute
+	Copied: false
+	Class: debugging and measurement(2)
+	Number: 18
+	Full Length: 12
+	ID: 42
+	Outbound hops: 3
+	Return hops: 65535
+	Originator: 192.168.0.1
+`,
+		`
+Option:
+	Type: MTUR(12)
+	Type description: MTU Reply
+	Copied: false
+	Class: control(0)
+	Number: 12
+	Full Length: 4
+	MTU: 1400
+`,
+	}
+
+	for i := range options {
+		tests.AssertStringer(t, &options[i], expectedStrings[i])
+	}
+}
+
+func TestFixedLayoutOptionsInvalid(t *testing.T) {
+	options := parseOptionsFromHeader(t, []byte{
+		0x94, 0x03, 0x00,
+		0x52, 0x04, 0x00, 0x01,
+		0x00,
+	})
+
+	_, err := options[0].RouterAlert()
+	assertOptionParseError(t, err, "option RTRALT(148) length", 21, "4", 3)
+
+	_, err = options[1].Traceroute()
+	assertOptionParseError(t, err, "option TR(82) length", 24, "12", 4)
+
+	_, err = options[1].MTU()
+	require.ErrorIs(t, err, v4.ErrWrongOptionType, "should not decode traceroute as MTU")
+
+	expectedString := `
+Option:
+	Type: RTRALT(148)
+	Type description: Router Alert
+	Copied: true
+	Class: control(0)
+	Number: 20
+	Full Length: 3
+	Hex data:
+		0x00
+`
+	tests.AssertStringer(t, &options[0], expectedString)
+}
+
+func TestEncodeOptions(t *testing.T) {
+	recordRoute, err := v4.NewRecordRouteOption(2)
+	require.NoError(t, err, "should create record route")
+
+	looseRoute, err := v4.NewLooseSourceRouteOption(net.IPv4(10, 0, 0, 1))
+	require.NoError(t, err, "should create loose source route")
+
+	timestamp, err := v4.NewTimestampOption(v4.TimestampOption{
+		Flag:    v4.TimestampWithAddress,
+		Entries: []v4.TimestampEntry{{Address: net.IPv4(10, 0, 0, 3)}},
+	})
+	require.NoError(t, err, "should create timestamp")
+
+	encoded, err := v4.EncodeOptions([]v4.Option{
+		v4.NewRouterAlertOption(v4.RouterAlertExaminePacket),
+		recordRoute,
+		v4.NewNOPOption(),
+		looseRoute,
+		timestamp,
+	})
+	require.NoError(t, err, "should encode options")
+
+	expected := []byte{
+		0x94, 0x04, 0x00, 0x00,
+		0x07, 0x0b, 0x04, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x01,
+		0x83, 0x07, 0x04, 0x0a, 0x00, 0x00, 0x01,
+		0x44, 0x0c, 0x05, 0x01, 0x0a, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00, 0x00,
+		0x00,
+	}
+	require.Equal(t, expected, encoded, "should encode options with EOOL padding")
+
+	options := parseOptionsFromHeader(t, encoded)
+	require.Len(t, options, 5, "should parse encoded options")
+
+	route, err := options[1].Route()
+	require.NoError(t, err, "should decode record route")
+	require.Equal(t, 0, route.NextIndex(), "record route should be empty")
+	require.Len(t, route.Addresses, 2)
+
+	_, err = v4.EncodeOptions([]v4.Option{recordRoute, recordRoute, recordRoute, recordRoute})
+	require.ErrorIs(t, err, netpacket.ErrCannotSerialize, "should not encode options longer than 40 bytes")
+}
+
+func TestOptionConstructors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		option   func() (v4.Option, error)
+		expected []byte
+	}{
+		{
+			name:     "EOOL",
+			option:   func() (v4.Option, error) { return v4.NewEndOfListOption(), nil },
+			expected: []byte{0x00},
+		},
+		{
+			name: "strict source route",
+			option: func() (v4.Option, error) {
+				return v4.NewStrictSourceRouteOption(net.IPv4(1, 2, 3, 4), net.IPv4(5, 6, 7, 8))
+			},
+			expected: []byte{0x89, 0x0b, 0x04, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08},
+		},
+		{
+			name: "quick start",
+			option: func() (v4.Option, error) {
+				return v4.NewQuickStartOption(v4.QuickStartOption{Function: v4.QuickStartRateReport, Rate: 5, Nonce: 1})
+			},
+			expected: []byte{0x19, 0x08, 0x85, 0x00, 0x00, 0x00, 0x00, 0x04},
+		},
+		{
+			name:     "stream ID",
+			option:   func() (v4.Option, error) { return v4.NewStreamIDOption(0x0102), nil },
+			expected: []byte{0x88, 0x04, 0x01, 0x02},
+		},
+		{
+			name:     "MTU probe",
+			option:   func() (v4.Option, error) { return v4.NewMTUProbeOption(1500), nil },
+			expected: []byte{0x0b, 0x04, 0x05, 0xdc},
+		},
+		{
+			name:     "MTU reply",
+			option:   func() (v4.Option, error) { return v4.NewMTUReplyOption(576), nil },
+			expected: []byte{0x0c, 0x04, 0x02, 0x40},
+		},
+		{
+			name: "traceroute",
+			option: func() (v4.Option, error) {
+				return v4.NewTracerouteOption(v4.TracerouteOption{
+					ID:           1,
+					OutboundHops: 2,
+					ReturnHops:   3,
+					Originator:   net.IPv4(10, 0, 0, 1),
+				})
+			},
+			expected: []byte{0x52, 0x0c, 0x00, 0x01, 0x00, 0x02, 0x00, 0x03, 0x0a, 0x00, 0x00, 0x01},
+		},
+		{
+			name:     "raw",
+			option:   func() (v4.Option, error) { return v4.NewOption(v4.OptionType(0xDE), []byte{0x01}) },
+			expected: []byte{0xde, 0x03, 0x01},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			option, err := tc.option()
+			require.NoError(t, err, "should create option")
+			require.Equal(t, tc.expected, option.AppendTo(nil))
+			require.Equal(t, len(tc.expected), option.GetLength(), "length should be calculated")
+		})
+	}
+}
+
+func TestOptionConstructorsInvalid(t *testing.T) {
+	_, err := v4.NewOption(v4.OptionRouterAlert, []byte{0x00})
+	assertOptionParseError(t, err, "option RTRALT(148) length", 1, "4", 3)
+
+	_, err = v4.NewOption(v4.OptionNoOperation, []byte{0x00})
+	require.Error(t, err, "should not create NOP with data")
+
+	_, err = v4.NewOption(v4.OptionType(0xDE), make([]byte, 39))
+	require.Error(t, err, "should not create too long option")
+	require.Contains(t, err.Error(), "data length 39 too long")
+
+	_, err = v4.NewRouteOption(v4.OptionRouterAlert, v4.RouteOption{})
+	require.ErrorIs(t, err, v4.ErrWrongOptionType, "should not create route with wrong type")
+
+	_, err = v4.NewLooseSourceRouteOption(net.ParseIP("::1"))
+	require.Error(t, err, "should not create route with IPv6 address")
+
+	_, err = v4.NewQuickStartOption(v4.QuickStartOption{Rate: 16})
+	require.Error(t, err, "should not create quick start with invalid rate")
+}
+
+func TestHeaderSetOptions(t *testing.T) {
+	recordRoute, err := v4.NewRecordRouteOption(1)
+	require.NoError(t, err)
+
+	header := validHeaderForSerialize()
+	err = header.SetOptions([]v4.Option{v4.NewRouterAlertOption(v4.RouterAlertExaminePacket), recordRoute})
+	require.NoError(t, err, "should set options")
+	require.Equal(t, uint8(8), header.IHL, "IHL should include options")
+	require.Equal(t, 32, header.HeaderLen())
+	require.NoError(t, header.FixLengths(0))
+
+	data, err := header.MarshalBinary()
+	require.NoError(t, err, "should marshal header with options")
+
+	parsed, err := v4.ParseHeader(data)
+	require.NoError(t, err, "should parse header")
+
+	options, err := parsed.ParseOptions()
+	require.NoError(t, err, "should parse options")
+	require.Len(t, options, 2)
+	require.Equal(t, v4.OptionRouterAlert, options[0].GetType())
+	require.Equal(t, v4.OptionRecordRoute, options[1].GetType())
+
+	err = header.SetOptionsData(nil)
+	require.NoError(t, err, "should clear options")
+	require.Equal(t, uint8(5), header.IHL, "IHL should be reset")
+	require.Nil(t, header.Options)
+
+	err = header.SetOptionsData([]byte{0x01, 0x01})
+	require.ErrorIs(t, err, netpacket.ErrCannotSerialize, "should not set not aligned options")
+	require.Equal(t, uint8(5), header.IHL, "IHL should not be changed on error")
+}
+
+func TestOptionTypeFields(t *testing.T) {
+	testCases := []struct {
+		optionType v4.OptionType
+		copied     bool
+		class      v4.OptionClass
+		number     uint8
+	}{
+		{optionType: v4.OptionEndOfList, copied: false, class: v4.OptionClassControl, number: 0},
+		{optionType: v4.OptionRecordRoute, copied: false, class: v4.OptionClassControl, number: 7},
+		{optionType: v4.OptionTimeStamp, copied: false, class: v4.OptionClassDebuggingAndMeasurement, number: 4},
+		{optionType: v4.OptionLooseSourceRoute, copied: true, class: v4.OptionClassControl, number: 3},
+		{optionType: v4.OptionRouterAlert, copied: true, class: v4.OptionClassControl, number: 20},
+		{optionType: v4.OptionType(0xE8), copied: true, class: v4.OptionClassReservedSecond, number: 8},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.copied, tc.optionType.Copied(), "copied flag for %d", tc.optionType)
+		require.Equal(t, tc.class, tc.optionType.Class(), "class for %d", tc.optionType)
+		require.Equal(t, tc.number, tc.optionType.Number(), "number for %d", tc.optionType)
+	}
+}
+
+func TestUnknownOptionString(t *testing.T) {
+	options := parseOptionsFromHeader(t, []byte{0xc8, 0x04, 0x01, 0x02})
+
+	expectedString := `
+Option:
+	Type: UNKNOWN(200)
+	Type description: Unknown: 200 (copied true, class debugging and measurement, number 8)
+	Copied: true
+	Class: debugging and measurement(2)
+	Number: 8
+	Full Length: 4
+	Hex data:
+		0x01 0x02
+`
+	tests.AssertStringer(t, &options[0], expectedString)
+}
@@ -4,11 +4,13 @@
 package v4
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/name212/netpacket"
 	"github.com/name212/netpacket/net/ip/v4"
 	"github.com/name212/netpacket/tests"
+	"github.com/name212/netpacket/transport/tcp"
 	"github.com/name212/netpacket/transport/udp"
 	"github.com/stretchr/testify/require"
 )
@@ -25,6 +27,14 @@ func TestParseIPv4PacketFailIfDataShort(t *testing.T) {
 	_, err := v4.ParsePacket(ipPacket)
 	require.Error(t, err, "should fail to parse packet")
 
+	tests.AssertParseError(t, err, netpacket.ErrShortData, netpacket.ParseError{
+		Kind:     v4.Kind,
+		Field:    "total length",
+		Offset:   2,
+		Expected: "<= data length 20",
+		Actual:   60,
+	})
+
 	payload, err := v4.ExtractPayload(ipPacket)
 	// because packet data len valid but header invalid
 	require.NoError(t, err, "payload should be extracted")
@@ -57,9 +67,12 @@ IPv4 Packet:
 		Destination: 192.168.0.1
 		Protocol: TCP
 		TTL: 64
+		DSCP: CS0
+		ECN: Not-ECT
 		Header Size: 20
 		Packet Size: 20
 		Flags:
+			Reserved: false
 			Don't Fragment: true
 			More Fragments: false
 		No options set
@@ -93,9 +106,12 @@ IPv4 Packet:
 		Destination: 8.8.8.8
 		Protocol: ICMP
 		TTL: 64
+		DSCP: CS0
+		ECN: Not-ECT
 		Header Size: 20
 		Packet Size: 84
 		Flags:
+			Reserved: false
 			Don't Fragment: true
 			More Fragments: false
 		No options set
@@ -154,20 +170,29 @@ IPv4 Packet:
 		Destination: 149.171.126.11
 		Protocol: ICMP
 		TTL: 254
+		DSCP: CS0
+		ECN: Not-ECT
 		Header Size: 36
 		Packet Size: 100
 		Flags:
+			Reserved: false
 			Don't Fragment: false
 			More Fragments: false
 		Options:
 			Option:
 				Type: NOP(1)
 				Type description: No Operation
+				Copied: false
+				Class: control(0)
+				Number: 1
 				Full Length: 1
 				No data
 			Option:
 				Type: SEC(130)
 				Type description: Security RIPSO
+				Copied: true
+				Class: control(0)
+				Number: 2
 				Full Length: 11
 				Hex data:
 					0x00 0x00 0x00 0x00 0x00 0x00 0x00 0x00
@@ -189,48 +214,33 @@ func TestGetTransportPacket(t *testing.T) {
 	})
 
 	t.Run("UDP", func(t *testing.T) {
-		ipPacket := []byte{
-			0x45, 0x00, 0x00, 0x38, 0x56, 0xaf, 0x40, 0x00, 0x40, 0x11, 0x25, 0xe0, 0xac, 0x11,
-			0x00, 0x03, 0x09, 0x09, 0x09, 0x09, 0x99, 0x7a, 0x00, 0x35, 0x00, 0x24, 0xbe, 0x5b,
-			0x42, 0x22, 0x01, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x06, 0x67,
-			0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x03, 0x63, 0x6f, 0x6d, 0x00, 0x00, 0x01, 0x00, 0x01,
-		}
-		packet := parsePacket(t, ipPacket, 56, 36)
+		packet := parsePacket(t, udpPacketData, 56, 36)
 		transport, err := packet.TransportPacket()
 		require.NoError(t, err, "transport packet should extracted")
 
 		assertTransport(t, transport, 39290, 53, udp.Kind, 28)
 
-		convertToUDP := func() {
-			v4.ToUDP(transport)
-		}
+		datagram, ok := v4.ToUDP(transport)
+		require.True(t, ok, "should convert to UDP")
+		require.NotNil(t, datagram)
 
-		require.NotPanics(t, convertToUDP, "should not be able to convert to UDP")
+		_, ok = v4.ToTCP(transport)
+		require.False(t, ok, "should not convert UDP to TCP")
 	})
 
 	t.Run("TCP", func(t *testing.T) {
-		ipPacket := []byte{
-			0x45, 0x00, 0x00, 0x71, 0xd1, 0x69, 0x40, 0x00, 0x40, 0x06, 0xce, 0xc9, 0x0a, 0xe9, 0xe9, 0x01,
-			0xd8, 0x3a, 0xce, 0x2e, 0xa7, 0x9e, 0x00, 0x50, 0x00, 0x4d, 0x6b, 0xcc, 0xb7, 0x16, 0x2a, 0x58,
-			0x50, 0x18, 0xfa, 0xf0, 0x50, 0xc5, 0x00, 0x00, 0x47, 0x45, 0x54, 0x20, 0x2f, 0x20, 0x48, 0x54,
-			0x54, 0x50, 0x2f, 0x31, 0x2e, 0x31, 0x0d, 0x0a, 0x48, 0x6f, 0x73, 0x74, 0x3a, 0x20, 0x67, 0x6f,
-			0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x63, 0x6f, 0x6d, 0x0d, 0x0a, 0x55, 0x73, 0x65, 0x72, 0x2d, 0x41,
-			0x67, 0x65, 0x6e, 0x74, 0x3a, 0x20, 0x63, 0x75, 0x72, 0x6c, 0x2f, 0x38, 0x2e, 0x35, 0x2e, 0x30,
-			0x0d, 0x0a, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x3a, 0x20, 0x2a, 0x2f, 0x2a, 0x0d, 0x0a, 0x0d,
-			0x0a,
-		}
-		packet := parsePacket(t, ipPacket, 113, 93)
+		packet := parsePacket(t, tcpPacketData, 113, 93)
 		transport, err := packet.TransportPacket()
 		require.NoError(t, err, "transport packet should parsed")
 
-		// todo should implement
-		// assertTransport(t, transport, 39290, 53, udp.Kind, 28)
+		assertTransport(t, transport, 42910, 80, tcp.Kind, 73)
 
-		convertToTCP := func() {
-			v4.ToTCP(transport)
-		}
+		tcpPacket, ok := v4.ToTCP(transport)
+		require.True(t, ok, "should convert to TCP")
+		require.NotNil(t, tcpPacket)
 
-		require.NotPanics(t, convertToTCP, "should not be able to convert to UDP")
+		_, ok = v4.ToUDP(transport)
+		require.False(t, ok, "should not convert TCP to UDP")
 	})
 }
 
@@ -261,6 +271,28 @@ var icmpValidPacketData = []byte{
 	0x34, 0x35, 0x36, 0x37,
 }
 
+// udpPacketData
+// DNS request from 172.17.0.3:39290 to 9.9.9.9:53 with partial (offloaded) UDP checksum
+var udpPacketData = []byte{
+	0x45, 0x00, 0x00, 0x38, 0x56, 0xaf, 0x40, 0x00, 0x40, 0x11, 0x25, 0xe0, 0xac, 0x11,
+	0x00, 0x03, 0x09, 0x09, 0x09, 0x09, 0x99, 0x7a, 0x00, 0x35, 0x00, 0x24, 0xbe, 0x5b,
+	0x42, 0x22, 0x01, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x06, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x03, 0x63, 0x6f, 0x6d, 0x00, 0x00, 0x01, 0x00, 0x01,
+}
+
+// tcpPacketData
+// HTTP request from 10.233.233.1:42910 to 216.58.206.46:80 with valid TCP checksum
+var tcpPacketData = []byte{
+	0x45, 0x00, 0x00, 0x71, 0xd1, 0x69, 0x40, 0x00, 0x40, 0x06, 0xce, 0xc9, 0x0a, 0xe9, 0xe9, 0x01,
+	0xd8, 0x3a, 0xce, 0x2e, 0xa7, 0x9e, 0x00, 0x50, 0x00, 0x4d, 0x6b, 0xcc, 0xb7, 0x16, 0x2a, 0x58,
+	0x50, 0x18, 0xfa, 0xf0, 0x50, 0xc5, 0x00, 0x00, 0x47, 0x45, 0x54, 0x20, 0x2f, 0x20, 0x48, 0x54,
+	0x54, 0x50, 0x2f, 0x31, 0x2e, 0x31, 0x0d, 0x0a, 0x48, 0x6f, 0x73, 0x74, 0x3a, 0x20, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x63, 0x6f, 0x6d, 0x0d, 0x0a, 0x55, 0x73, 0x65, 0x72, 0x2d, 0x41,
+	0x67, 0x65, 0x6e, 0x74, 0x3a, 0x20, 0x63, 0x75, 0x72, 0x6c, 0x2f, 0x38, 0x2e, 0x35, 0x2e, 0x30,
+	0x0d, 0x0a, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x3a, 0x20, 0x2a, 0x2f, 0x2a, 0x0d, 0x0a, 0x0d,
+	0x0a,
+}
+
 func icmpValidPacket(t *testing.T) *v4.Packet {
 	return parsePacket(t, icmpValidPacketData, 84, 64)
 }
@@ -273,3 +305,71 @@ func assertTransport(t *testing.T, transport v4.Transport, srcPort, dstPort int,
 	require.Equal(t, kind, transport.Kind(), "transport kind should be %s", kind)
 	require.Len(t, transport.GetPayload(), payloadLen, "payload len should be %d", payloadLen)
 }
+
+func TestParseIPv4PacketWithTrailer(t *testing.T) {
+	padding := []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+	data := slices.Concat(udpPacketData, padding)
+
+	packet := parsePacket(t, data, 56, 36)
+	require.Equal(t, udpPacketData[20:], packet.GetPayload(), "payload should be limited by total length")
+	require.Equal(t, padding, packet.GetTrailer(), "padding should be returned as trailer")
+	require.False(t, packet.IsTruncated(), "packet should not be truncated")
+	require.True(t, packet.Anomalies().Has(netpacket.AnomalyTrailingData), "trailer should be reported")
+
+	payload, err := v4.ExtractPayload(data)
+	require.NoError(t, err)
+	require.Equal(t, udpPacketData[20:], payload, "extracted payload should be limited by total length")
+
+	transport, err := packet.TransportPacket()
+	require.NoError(t, err)
+	assertTransport(t, transport, 39290, 53, udp.Kind, 28)
+	datagram, ok := v4.ToUDP(transport)
+	require.True(t, ok)
+	require.Empty(t, datagram.Anomalies(), "padding should not leak into UDP datagram")
+
+	serialized, err := packet.MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, udpPacketData, serialized, "trailer should not be serialized")
+
+	require.Equal(t, padding, packet.Clone().GetTrailer(), "clone should keep trailer")
+	require.Empty(t, parsePacket(t, udpPacketData, 56, 36).GetTrailer(), "packet without padding has no trailer")
+}
+
+func TestParseIPv4PacketTruncated(t *testing.T) {
+	const snapLen = 40
+
+	data := tcpPacketData[:snapLen]
+
+	_, err := v4.ParsePacket(data)
+	require.ErrorIs(t, err, netpacket.ErrShortData, "truncated packet should be rejected by default")
+
+	packet, err := v4.ParsePacketWithOptions(data, netpacket.ParseOptions{AllowTruncated: true})
+	require.NoError(t, err, "truncated packet should be parsed with AllowTruncated")
+	require.True(t, packet.IsTruncated(), "packet should be marked as truncated")
+	require.Equal(t, 113, packet.GetHeader().GetTotalLen(), "total length should be kept")
+	require.Equal(t, tcpPacketData[20:snapLen], packet.GetPayload(), "available payload should be returned")
+	require.Empty(t, packet.GetTrailer())
+	require.True(t, packet.Anomalies().Has(netpacket.AnomalyLengthExceedsData), "truncation should be reported")
+	require.True(t, packet.Clone().IsTruncated(), "clone should keep truncated mark")
+
+	transport, err := packet.TransportPacket()
+	require.NoError(t, err, "TCP header should be decoded from truncated packet")
+	assertTransport(t, transport, 42910, 80, tcp.Kind, 0)
+
+	full, err := v4.ParsePacketWithOptions(tcpPacketData, netpacket.ParseOptions{AllowTruncated: true})
+	require.NoError(t, err)
+	require.False(t, full.IsTruncated(), "full packet should not be marked as truncated")
+	require.Empty(t, full.Anomalies())
+
+	udpPacket, err := v4.ParsePacketWithOptions(udpPacketData[:snapLen], netpacket.ParseOptions{AllowTruncated: true})
+	require.NoError(t, err, "truncated UDP packet should be parsed with AllowTruncated")
+	require.True(t, udpPacket.IsTruncated(), "UDP packet should be marked as truncated")
+
+	transport, err = udpPacket.TransportPacket()
+	require.NoError(t, err, "UDP header should be decoded from truncated packet")
+	assertTransport(t, transport, 39290, 53, udp.Kind, snapLen-28)
+
+	datagram, ok := transport.(*udp.Datagram)
+	require.True(t, ok, "transport should be UDP datagram")
+	require.True(t, datagram.Anomalies().Has(netpacket.AnomalyLengthExceedsData), "UDP truncation should be reported")
+}
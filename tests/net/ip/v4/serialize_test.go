@@ -0,0 +1,134 @@
+// Copyright 2026
+// license that can be found in the LICENSE file.
+
+package v4
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/name212/netpacket"
+	"github.com/name212/netpacket/net/ip/v4"
+)
+
+func TestIPv4HeaderMarshalBinary(t *testing.T) {
+	data := []byte{
+		0x49, 0x00, 0x00, 0x28, 0x03, 0x04,
+		0x00, 0x00, 0xfe, 0x01, 0xc1, 0xe0,
+		0xaf, 0x2d, 0xb0, 0x00, 0x95, 0xab,
+		0x7e, 0x0b, 0x01, 0x82, 0x0b, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x01, 0x02, 0x03,
+	}
+
+	header := parseHeader(t, data, 40)
+
+	res, err := header.MarshalBinary()
+	require.NoError(t, err, "should marshal")
+	require.Equal(t, data, res, "marshaled header should be equal to original")
+
+	prefix := []byte{0xFF, 0xFF}
+	res, err = header.AppendTo(prefix)
+	require.NoError(t, err, "should append")
+	require.Equal(t, append([]byte{0xFF, 0xFF}, data...), res, "header should be appended")
+}
+
+func TestIPv4HeaderMarshalBinaryInvalid(t *testing.T) {
+	assertError := func(t *testing.T, header *v4.Header, errorContains string) {
+		t.Helper()
+
+		_, err := header.MarshalBinary()
+		require.ErrorIs(t, err, netpacket.ErrCannotSerialize, "should not marshal")
+		require.Contains(t, err.Error(), errorContains)
+	}
+
+	t.Run("not aligned options", func(t *testing.T) {
+		header := validHeaderForSerialize()
+		header.Options = []byte{0x01, 0x01, 0x01}
+		assertError(t, header, "IPv4 options length 3 is not multiple of 4")
+	})
+
+	t.Run("too long options", func(t *testing.T) {
+		header := validHeaderForSerialize()
+		header.Options = make([]byte, 44)
+		assertError(t, header, "IPv4 options length 44 exceeds 40")
+	})
+
+	t.Run("IHL does not match options", func(t *testing.T) {
+		header := validHeaderForSerialize()
+		header.Options = []byte{0x01, 0x01, 0x01, 0x00}
+		assertError(t, header, "IPv4 header length 20 does not match options length 4")
+	})
+
+	t.Run("not IPv4 address", func(t *testing.T) {
+		header := validHeaderForSerialize()
+		header.SourceIP = net.ParseIP("2001:db8::1")
+		assertError(t, header, "is not IPv4 address")
+	})
+}
+
+func TestIPv4PacketRoundTrip(t *testing.T) {
+	packet := parsePacket(t, icmpValidPacketData, 84, 64)
+
+	res, err := packet.MarshalBinary()
+	require.NoError(t, err, "should marshal")
+	require.Equal(t, icmpValidPacketData, res, "marshaled packet should be equal to original")
+
+	packet.GetHeader().TTL = 10
+
+	res, err = packet.Serialize(netpacket.SerializeOptions{ComputeChecksums: true})
+	require.NoError(t, err, "should serialize")
+
+	modified, err := v4.ParsePacketWithOptions(res, netpacket.ParseOptions{VerifyChecksum: true})
+	require.NoError(t, err, "should parse modified packet with valid checksum")
+	require.Equal(t, 10, modified.GetTTL(), "TTL should be changed")
+	require.Equal(t, packet.GetPayload(), modified.GetPayload(), "payload should not be changed")
+}
+
+func TestNewIPv4PacketSerialize(t *testing.T) {
+	header := validHeaderForSerialize()
+	header.Options = []byte{0x94, 0x04, 0x00, 0x00}
+
+	payload := []byte{0x01, 0x02, 0x03}
+	packet := v4.NewPacket(header, payload)
+
+	_, err := packet.MarshalBinary()
+	require.ErrorIs(t, err, netpacket.ErrCannotSerialize, "should not marshal without fix lengths")
+
+	res, err := packet.Serialize(netpacket.SerializeOptions{FixLengths: true, ComputeChecksums: true})
+	require.NoError(t, err, "should serialize")
+	require.Len(t, res, 27, "packet len should be 27")
+
+	require.Equal(t, uint8(6), header.IHL, "IHL should be fixed")
+	require.Equal(t, 27, header.GetTotalLen(), "total length should be fixed")
+
+	parsed, err := v4.ParsePacketWithOptions(res, netpacket.ParseOptions{VerifyChecksum: true})
+	require.NoError(t, err, "should parse serialized packet")
+
+	assertSourceAndDestinationAndProto(t, parsed.GetHeader(), "10.0.0.1", v4.ProtocolUDP, "10.0.0.2", "UDP")
+	require.Equal(t, payload, parsed.GetPayload(), "payload should be equal")
+	require.Equal(t, header.Options, parsed.GetHeader().Options, "options should be equal")
+	require.True(t, parsed.GetHeader().DontFragment(), "should be don't fragment")
+}
+
+func TestIPv4PacketSerializeTooLong(t *testing.T) {
+	packet := v4.NewPacket(validHeaderForSerialize(), make([]byte, 0xFFFF))
+
+	_, err := packet.Serialize(netpacket.SerializeOptions{FixLengths: true})
+	require.ErrorIs(t, err, netpacket.ErrCannotSerialize, "should not serialize")
+	require.Contains(t, err.Error(), "IPv4 total length 65555 exceeds 65535")
+}
+
+func validHeaderForSerialize() *v4.Header {
+	return &v4.Header{
+		Version:       4,
+		IHL:           5,
+		TTL:           64,
+		Flags:         v4.FlagDontFragment,
+		Protocol:      uint8(v4.ProtocolUDP),
+		SourceIP:      net.IPv4(10, 0, 0, 1),
+		DestinationIP: net.IPv4(10, 0, 0, 2),
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2026
+// license that can be found in the LICENSE file.
+
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/name212/netpacket"
+)
+
+func TestSerializeBuffer(t *testing.T) {
+	assertPrepend := func(t *testing.T, b *netpacket.SerializeBuffer, data ...byte) {
+		t.Helper()
+
+		bytes, err := b.PrependBytes(len(data))
+		require.NoError(t, err, "should prepend")
+		copy(bytes, data)
+	}
+
+	assertAppend := func(t *testing.T, b *netpacket.SerializeBuffer, data ...byte) {
+		t.Helper()
+
+		bytes, err := b.AppendBytes(len(data))
+		require.NoError(t, err, "should append")
+		copy(bytes, data)
+	}
+
+	for name, b := range map[string]*netpacket.SerializeBuffer{
+		"empty":           netpacket.NewSerializeBuffer(),
+		"expected size":   netpacket.NewSerializeBufferExpectedSize(4, 4),
+		"small prepended": netpacket.NewSerializeBufferExpectedSize(1, 0),
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.Empty(t, b.Bytes(), "new buffer should be empty")
+
+			assertAppend(t, b, 0x03, 0x04)
+			assertPrepend(t, b, 0x02)
+			assertPrepend(t, b, 0x00, 0x01)
+			assertAppend(t, b, 0x05)
+
+			require.Equal(t, []byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05}, b.Bytes())
+
+			b.Clear()
+			require.Empty(t, b.Bytes(), "cleared buffer should be empty")
+
+			assertPrepend(t, b, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08)
+			require.Equal(t, []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08}, b.Bytes())
+		})
+	}
+
+	_, err := netpacket.NewSerializeBuffer().PrependBytes(-1)
+	require.ErrorIs(t, err, netpacket.ErrCannotSerialize, "should not prepend negative count")
+}
+
+func TestSerializeLayers(t *testing.T) {
+	b := netpacket.NewSerializeBuffer()
+
+	err := netpacket.SerializeLayers(b, netpacket.SerializeOptions{},
+		netpacket.Payload{0x01, 0x02},
+		netpacket.Payload{0x03},
+		netpacket.Payload{0x04, 0x05},
+	)
+	require.NoError(t, err, "should serialize")
+	require.Equal(t, []byte{0x01, 0x02, 0x03, 0x04, 0x05}, b.Bytes())
+
+	err = netpacket.SerializeLayers(b, netpacket.SerializeOptions{}, netpacket.Payload{0x06})
+	require.NoError(t, err, "should serialize")
+	require.Equal(t, []byte{0x06}, b.Bytes(), "buffer should be cleared before serialize")
+}
@@ -0,0 +1,48 @@
+// Copyright 2026
+// license that can be found in the LICENSE file.
+
+package tcp
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/name212/netpacket"
+	"github.com/name212/netpacket/tests"
+	"github.com/name212/netpacket/transport/tcp"
+)
+
+func TestTCPParseWithCopy(t *testing.T) {
+	data := slices.Clone(synSegment)
+
+	packet, err := tcp.ParsePacketWithOptions(data, netpacket.ParseOptions{Copy: true})
+	require.NoError(t, err, "should parse packet")
+
+	header, err := tcp.ParseHeaderWithOptions(data, netpacket.ParseOptions{Copy: true})
+	require.NoError(t, err, "should parse header")
+
+	tests.OverwriteBuffer(data)
+
+	require.Equal(t, synSegment, packet.GetHeaderData(), "header data should not alias data")
+	require.Equal(t, synSegment[20:], packet.GetHeader().Options, "packet options should not alias data")
+	require.Equal(t, synSegment[20:], header.Options, "header options should not alias data")
+	assertHeader(t, header, 42910, 80, 0x004d6bcb, 0, tcp.FlagSYN, 40)
+}
+
+func TestTCPClone(t *testing.T) {
+	data := slices.Clone(httpSegment)
+
+	packet, err := tcp.ParsePacket(data)
+	require.NoError(t, err, "should parse packet")
+
+	clone := packet.Clone()
+	require.Equal(t, packet.String(), clone.String(), "clone should be equal to packet")
+
+	tests.OverwriteBuffer(data)
+
+	require.Equal(t, httpSegment[20:], clone.GetPayload(), "payload should be copied")
+	require.NotEqual(t, httpSegment[20:], packet.GetPayload(), "original packet should alias data")
+	assertHeader(t, clone.GetHeader(), 42910, 80, 0x004d6bcc, 0xb7162a58, tcp.FlagPSH|tcp.FlagACK, 20)
+}
@@ -0,0 +1,46 @@
+// Copyright 2026
+// license that can be found in the LICENSE file.
+
+package tcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/name212/netpacket/transport/tcp"
+)
+
+// synSegment
+// SYN from 10.233.233.1:42910 to 216.58.206.46:80 with MSS, SACK permitted,
+// timestamps, NOP and window scale options
+var synSegment = []byte{
+	0xa7, 0x9e, 0x00, 0x50, 0x00, 0x4d, 0x6b, 0xcb, 0x00, 0x00, 0x00, 0x00,
+	0xa0, 0x02, 0xfa, 0xf0, 0xb2, 0x48, 0x00, 0x00,
+	0x02, 0x04, 0x05, 0xb4, 0x04, 0x02, 0x08, 0x0a, 0x9b, 0x3c, 0x51, 0x2f,
+	0x00, 0x00, 0x00, 0x00, 0x01, 0x03, 0x03, 0x07,
+}
+
+// httpSegment
+// PSH ACK with HTTP GET request payload from 10.233.233.1:42910 to 216.58.206.46:80
+var httpSegment = []byte{
+	0xa7, 0x9e, 0x00, 0x50, 0x00, 0x4d, 0x6b, 0xcc, 0xb7, 0x16, 0x2a, 0x58,
+	0x50, 0x18, 0xfa, 0xf0, 0x50, 0xc5, 0x00, 0x00, 0x47, 0x45, 0x54, 0x20,
+	0x2f, 0x20, 0x48, 0x54, 0x54, 0x50, 0x2f, 0x31, 0x2e, 0x31, 0x0d, 0x0a,
+	0x48, 0x6f, 0x73, 0x74, 0x3a, 0x20, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x63, 0x6f, 0x6d, 0x0d, 0x0a, 0x55, 0x73, 0x65, 0x72, 0x2d, 0x41,
+	0x67, 0x65, 0x6e, 0x74, 0x3a, 0x20, 0x63, 0x75, 0x72, 0x6c, 0x2f, 0x38,
+	0x2e, 0x35, 0x2e, 0x30, 0x0d, 0x0a, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74,
+	0x3a, 0x20, 0x2a, 0x2f, 0x2a, 0x0d, 0x0a, 0x0d, 0x0a,
+}
+
+func assertHeader(t *testing.T, header *tcp.Header, srcPort, dstPort int, seq, ack uint32, flags tcp.Flags, headerLen int) {
+	t.Helper()
+
+	require.Equal(t, srcPort, header.GetSourcePort(), "source port should be %d", srcPort)
+	require.Equal(t, dstPort, header.GetDestinationPort(), "destination port should be %d", dstPort)
+	require.Equal(t, seq, header.SequenceNumber, "sequence number should be %d", seq)
+	require.Equal(t, ack, header.AckNumber, "ack number should be %d", ack)
+	require.Equal(t, flags, header.GetFlags(), "flags should be %s", flags)
+	require.Equal(t, headerLen, header.HeaderLen(), "header len should be %d", headerLen)
+}
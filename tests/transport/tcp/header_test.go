@@ -0,0 +1,155 @@
+// Copyright 2026
+// license that can be found in the LICENSE file.
+
+package tcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/name212/netpacket"
+	"github.com/name212/netpacket/tests"
+	"github.com/name212/netpacket/transport/tcp"
+)
+
+func TestParseTCPHeaderShortData(t *testing.T) {
+	header, err := tcp.ParseHeader(httpSegment[:10])
+	require.Error(t, err, "should not parse")
+	require.Nil(t, header)
+
+	tests.AssertParseError(t, err, netpacket.ErrShortData, netpacket.ParseError{
+		Kind:     tcp.Kind,
+		Field:    "data length",
+		Offset:   0,
+		Expected: ">= 20",
+		Actual:   10,
+	})
+}
+
+func TestParseTCPHeaderInvalidDataOffset(t *testing.T) {
+	t.Run("too small", func(t *testing.T) {
+		data := append([]byte{}, httpSegment[:20]...)
+		data[12] = 0x40
+
+		header, err := tcp.ParseHeader(data)
+		require.Error(t, err, "should not parse")
+		require.Nil(t, header)
+
+		tests.AssertParseError(t, err, netpacket.ErrCannotParseHeader, netpacket.ParseError{
+			Kind:     tcp.Kind,
+			Field:    "header length",
+			Offset:   12,
+			Expected: ">= 20",
+			Actual:   16,
+		})
+	})
+
+	t.Run("exceeds data", func(t *testing.T) {
+		header, err := tcp.ParseHeader(synSegment[:24])
+		require.Error(t, err, "should not parse")
+		require.Nil(t, header)
+
+		tests.AssertParseError(t, err, netpacket.ErrShortData, netpacket.ParseError{
+			Kind:     tcp.Kind,
+			Field:    "header length",
+			Offset:   12,
+			Expected: "<= data length 24",
+			Actual:   40,
+		})
+	})
+}
+
+func TestParseTCPHeader(t *testing.T) {
+	header, err := tcp.ParseHeader(httpSegment)
+	require.NoError(t, err, "should parse")
+	require.NotNil(t, header)
+
+	assertHeader(t, header, 42910, 80, 5073868, 3071683160, tcp.FlagPSH|tcp.FlagACK, 20)
+	require.Equal(t, uint16(64240), header.Window, "window should be 64240")
+	require.Equal(t, uint16(20677), header.Checksum, "checksum should be 20677")
+	require.Equal(t, uint8(0), header.Reserved, "reserved should be 0")
+	require.Nil(t, header.Options, "options should be empty")
+	require.False(t, header.Flags.Has(tcp.FlagSYN), "should not be SYN")
+
+	// AssertStringer Trim \n from expected
+	// use \n this for better observability (show in code as string present)
+	expectedString := `
+Source port: 42910
+Destination port: 80
+Sequence number: 5073868
+Acknowledgment number: 3071683160
+Header Size: 20
+Reserved: 0
+Flags: ACK PSH
+Window: 64240
+Urgent pointer: 0
+No options set
+Checksum: 20677
+`
+	tests.AssertStringer(t, header, expectedString)
+}
+
+func TestParseTCPHeaderWithOptions(t *testing.T) {
+	header, err := tcp.ParseHeader(synSegment)
+	require.NoError(t, err, "should parse")
+	require.NotNil(t, header)
+
+	assertHeader(t, header, 42910, 80, 5073867, 0, tcp.FlagSYN, 40)
+	require.Len(t, header.Options, 20, "options len should be 20")
+
+	// AssertStringer Trim \n from expected
+	// use \n this for better observability (show in code as string present)
+	expectedString := `
+Source port: 42910
+Destination port: 80
+Sequence number: 5073867
+Acknowledgment number: 0
+Header Size: 40
+Reserved: 0
+Flags: SYN
+Window: 64240
+Urgent pointer: 0
+Options:
+	Option:
+		Kind: MSS(2)
+		Kind description: Maximum Segment Size
+		Full Length: 4
+		MSS: 1460
+	Option:
+		Kind: SACK-P(4)
+		Kind description: SACK Permitted
+		Full Length: 2
+		No data
+	Option:
+		Kind: TS(8)
+		Kind description: Timestamps
+		Full Length: 10
+		TSval: 2604421423
+		TSecr: 0
+	Option:
+		Kind: NOP(1)
+		Kind description: No Operation
+		Full Length: 1
+		No data
+	Option:
+		Kind: WS(3)
+		Kind description: Window Scale
+		Full Length: 3
+		Shift count: 7
+Checksum: 45640
+`
+	tests.AssertStringer(t, header, expectedString)
+}
+
+func TestTCPFlags(t *testing.T) {
+	data := append([]byte{}, httpSegment[:20]...)
+	data[12] |= 0x01
+	data[13] = 0xFF
+
+	header, err := tcp.ParseHeader(data)
+	require.NoError(t, err, "should parse")
+
+	require.Equal(t, "AE CWR ECE URG ACK PSH RST SYN FIN", header.Flags.String())
+	require.Equal(t, "none", tcp.Flags(0).String())
+}
@@ -0,0 +1,225 @@
+// Copyright 2026
+// license that can be found in the LICENSE file.
+
+package tcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/name212/netpacket"
+	"github.com/name212/netpacket/tests"
+	"github.com/name212/netpacket/transport/tcp"
+)
+
+func TestParseTCPOptionsFromSYN(t *testing.T) {
+	header, err := tcp.ParseHeader(synSegment)
+	require.NoError(t, err, "should parse")
+
+	options, err := header.ParseOptions()
+	require.NoError(t, err, "should parse options")
+	require.Len(t, options, 5, "options len should be 5")
+
+	mssOption := options[0]
+	assertOptionKindAndLength(t, mssOption, tcp.OptionMSS, "MSS", 4)
+	mss, err := mssOption.MSS()
+	require.NoError(t, err)
+	require.Equal(t, uint16(1460), mss, "mss should be 1460")
+
+	_, err = mssOption.WindowScale()
+	require.ErrorIs(t, err, tcp.ErrWrongOptionKind, "should not get window scale from MSS")
+
+	sackPermitted := options[1]
+	assertOptionKindAndLength(t, sackPermitted, tcp.OptionSACKPermitted, "SACK-P", 2)
+	require.True(t, sackPermitted.IsSACKPermitted(), "should be SACK permitted")
+	require.Empty(t, sackPermitted.GetData(), "data should be empty for SACK permitted")
+
+	tsOption := options[2]
+	assertOptionKindAndLength(t, tsOption, tcp.OptionTimestamps, "TS", 10)
+	ts, err := tsOption.Timestamps()
+	require.NoError(t, err)
+	require.Equal(t, &tcp.Timestamps{Value: 2604421423, EchoReply: 0}, ts)
+
+	assertOptionKindAndLength(t, options[3], tcp.OptionNoOperation, "NOP", 1)
+
+	wsOption := options[4]
+	assertOptionKindAndLength(t, wsOption, tcp.OptionWindowScale, "WS", 3)
+	shift, err := wsOption.WindowScale()
+	require.NoError(t, err)
+	require.Equal(t, uint8(7), shift, "window scale should be 7")
+
+	expectedWSString := `
+Option:
+	Kind: WS(3)
+	Kind description: Window Scale
+	Full Length: 3
+	Shift count: 7
+`
+	tests.AssertStringer(t, &wsOption, expectedWSString)
+}
+
+func TestParseTCPOptionsTyped(t *testing.T) {
+	t.Run("SACK", func(t *testing.T) {
+		option := parseSingleOption(t, []byte{
+			0x01, 0x01, 0x05, 0x12,
+			0x00, 0x00, 0x03, 0xe8, 0x00, 0x00, 0x07, 0xd0,
+			0x00, 0x00, 0x0b, 0xb8, 0x00, 0x00, 0x0f, 0xa0,
+		}, 2)
+
+		blocks, err := option.SACKBlocks()
+		require.NoError(t, err)
+		require.Equal(t, []tcp.SACKBlock{
+			{LeftEdge: 1000, RightEdge: 2000},
+			{LeftEdge: 3000, RightEdge: 4000},
+		}, blocks)
+
+		expectedString := `
+Option:
+	Kind: SACK(5)
+	Kind description: Selective Acknowledgment
+	Full Length: 18
+	Blocks:
+		1000-2000
+		3000-4000
+`
+		tests.AssertStringer(t, &option, expectedString)
+	})
+
+	t.Run("Fast Open", func(t *testing.T) {
+		option := parseSingleOption(t, []byte{
+			0x22, 0x0a, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08,
+			0x01, 0x01,
+		}, 0)
+
+		cookie, err := option.FastOpenCookie()
+		require.NoError(t, err)
+		require.Equal(t, []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08}, cookie)
+
+		request := parseSingleOption(t, []byte{0x22, 0x02, 0x01, 0x01}, 0)
+		cookie, err = request.FastOpenCookie()
+		require.NoError(t, err)
+		require.Empty(t, cookie, "cookie request should not contain cookie")
+	})
+
+	t.Run("MPTCP", func(t *testing.T) {
+		option := parseSingleOption(t, []byte{
+			0x1e, 0x0c, 0x00, 0x81, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08,
+		}, 0)
+
+		subtype, err := option.MPTCPSubtype()
+		require.NoError(t, err)
+		require.Equal(t, tcp.MPTCPCapable, subtype)
+		require.Equal(t, "MP_CAPABLE(0)", subtype.String())
+	})
+
+	t.Run("MD5", func(t *testing.T) {
+		data := make([]byte, 20)
+		data[0] = 0x13
+		data[1] = 0x12
+		data[2] = 0xAA
+		data[18] = 0x01
+		data[19] = 0x01
+
+		option := parseSingleOption(t, data, 0)
+		signature, err := option.MD5Signature()
+		require.NoError(t, err)
+		require.Len(t, signature, 16, "signature len should be 16")
+		require.Equal(t, byte(0xAA), signature[0])
+	})
+
+	t.Run("TCP-AO", func(t *testing.T) {
+		option := parseSingleOption(t, []byte{0x1d, 0x08, 0x05, 0x06, 0x11, 0x22, 0x33, 0x44}, 0)
+
+		ao, err := option.Authentication()
+		require.NoError(t, err)
+		require.Equal(t, &tcp.AuthenticationOption{
+			KeyID:      5,
+			RNextKeyID: 6,
+			MAC:        []byte{0x11, 0x22, 0x33, 0x44},
+		}, ao)
+	})
+}
+
+func TestParseTCPOptionsMalformed(t *testing.T) {
+	assertLengthError := func(t *testing.T, options []byte, field, expected string, actual int) {
+		t.Helper()
+
+		header := &tcp.Header{Options: options}
+		_, err := header.ParseOptions()
+
+		tests.AssertParseError(t, err, netpacket.ErrCannotParseHeader, netpacket.ParseError{
+			Kind:     tcp.Kind,
+			Field:    field,
+			Offset:   21,
+			Expected: expected,
+			Actual:   actual,
+		})
+	}
+
+	t.Run("MSS invalid length", func(t *testing.T) {
+		assertLengthError(t, []byte{0x02, 0x03, 0x05, 0x00}, "option MSS(2) length", "4", 3)
+
+		_, err := tcp.NewOption(tcp.OptionMSS, []byte{0x05})
+		require.Error(t, err, "should not create MSS with invalid length")
+		require.Contains(t, err.Error(), "option MSS(2): invalid length 3. Must be 4")
+	})
+
+	t.Run("TCP-AO too short", func(t *testing.T) {
+		assertLengthError(t, []byte{0x1d, 0x03, 0x05, 0x00}, "option TCP-AO(29) length", ">= 4", 3)
+	})
+
+	t.Run("length exceeds", func(t *testing.T) {
+		header := &tcp.Header{Options: []byte{0x01, 0x08, 0x0a, 0x00, 0x00, 0x00, 0x00, 0x00}}
+		_, err := header.ParseOptions()
+
+		tests.AssertParseError(t, err, netpacket.ErrShortData, netpacket.ParseError{
+			Kind:     tcp.Kind,
+			Field:    "option TS(8) length",
+			Offset:   22,
+			Expected: "<= remaining header length 7",
+			Actual:   10,
+		})
+	})
+
+	t.Run("length too small", func(t *testing.T) {
+		header := &tcp.Header{Options: []byte{0x03, 0x01, 0x00, 0x00}}
+		_, err := header.ParseOptions()
+
+		tests.AssertParseError(t, err, netpacket.ErrCannotParseHeader, netpacket.ParseError{
+			Kind:     tcp.Kind,
+			Field:    "option WS(3) length",
+			Offset:   21,
+			Expected: ">= 2",
+			Actual:   1,
+		})
+		require.Contains(t, err.Error(), "cannot parse header: TCP at offset 21: option WS(3) length expected >= 2, actual 1")
+	})
+
+	t.Run("SACK without blocks", func(t *testing.T) {
+		assertLengthError(t, []byte{0x05, 0x06, 0x00, 0x00, 0x00, 0x00}, "option SACK(5) length", "2 + 8*n where n from 1 to 4", 6)
+	})
+
+	t.Run("Fast Open odd cookie", func(t *testing.T) {
+		assertLengthError(t, []byte{0x22, 0x07, 0x01, 0x02, 0x03, 0x04, 0x05, 0x00}, "option TFO(34) length", "2 or 2 + even cookie length from 4 to 16", 7)
+	})
+}
+
+func parseSingleOption(t *testing.T, options []byte, index int) tcp.Option {
+	t.Helper()
+
+	header := &tcp.Header{Options: options}
+	parsed, err := header.ParseOptions()
+	require.NoError(t, err, "should parse options")
+	require.Greater(t, len(parsed), index, "should contain option")
+
+	return parsed[index]
+}
+
+func assertOptionKindAndLength(t *testing.T, option tcp.Option, kind tcp.OptionKind, short string, length int) {
+	t.Helper()
+
+	require.Equal(t, kind, option.GetKind(), "option kind should be %d", kind)
+	require.Equal(t, length, option.GetLength(), "option length should be %d", length)
+	require.Equal(t, short, option.KindShort(), "option kind should be %s", short)
+}
@@ -0,0 +1,114 @@
+// Copyright 2026
+// license that can be found in the LICENSE file.
+
+package tcp
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/name212/netpacket"
+	"github.com/name212/netpacket/tests"
+	"github.com/name212/netpacket/transport/tcp"
+)
+
+func TestParseTCPPacketShortData(t *testing.T) {
+	data := httpSegment[:12]
+
+	packet, err := tcp.ParsePacket(data)
+	require.Error(t, err, "should not parse")
+	require.ErrorIs(t, err, netpacket.ErrCannotParseHeader, "should be cannot parse header error")
+	require.Nil(t, packet)
+
+	payload, err := tcp.ExtractPayload(data)
+	require.Error(t, err, "should not extract payload")
+	require.ErrorIs(t, err, netpacket.ErrShortData, "should be short data error")
+	require.Nil(t, payload, "should extract empty payload")
+}
+
+func TestParseTCPPacketWithoutPayload(t *testing.T) {
+	packet := parsePacket(t, synSegment)
+
+	assertHeader(t, packet.GetHeader(), 42910, 80, 5073867, 0, tcp.FlagSYN, 40)
+	require.Equal(t, 42910, packet.GetSourcePort(), "source port should be 42910")
+	require.Equal(t, 80, packet.GetDestinationPort(), "destination port should be 80")
+	require.Len(t, packet.GetHeaderData(), 40, "header data len should be 40")
+	require.Empty(t, packet.GetPayload(), "should parse empty payload")
+
+	payload, err := tcp.ExtractPayload(synSegment)
+	require.NoError(t, err, "should extract payload")
+	require.Empty(t, payload, "should extract empty payload")
+}
+
+func TestParseTCPPacketWithPayload(t *testing.T) {
+	const payloadLength = 73
+
+	packet := parsePacket(t, httpSegment)
+
+	assertHeader(t, packet.GetHeader(), 42910, 80, 5073868, 3071683160, tcp.FlagPSH|tcp.FlagACK, 20)
+	require.Len(t, packet.GetHeaderData(), 20, "header data len should be 20")
+
+	expectedPayload := "R0VUIC8gSFRUUC8xLjENCkhvc3Q6IGdvb2dsZS5jb20NClVzZXItQWdlbnQ6IGN1cmwvOC41LjANCkFjY2VwdDogKi8qDQoNCg=="
+
+	tests.AssertDataAsBase64(t, expectedPayload, packet.GetPayload(), payloadLength)
+
+	payload, err := tcp.ExtractPayload(httpSegment)
+	require.NoError(t, err, "should extract payload")
+	tests.AssertDataAsBase64(t, expectedPayload, payload, payloadLength)
+
+	// AssertStringer Trim \n from expected
+	// use \n this for better observability (show in code as string present)
+	expectedString := `
+TCP Packet:
+	Header:
+		Source port: 42910
+		Destination port: 80
+		Sequence number: 5073868
+		Acknowledgment number: 3071683160
+		Header Size: 20
+		Reserved: 0
+		Flags: ACK PSH
+		Window: 64240
+		Urgent pointer: 0
+		No options set
+		Checksum: 20677
+	Checksum status: not verified
+	Payload len: 73
+`
+	tests.AssertStringer(t, packet, expectedString)
+}
+
+func parsePacket(t *testing.T, data []byte) *tcp.Packet {
+	t.Helper()
+
+	packet, err := tcp.ParsePacket(data)
+	require.NoError(t, err, "should parse")
+	require.NotNil(t, packet, "should parse")
+
+	return packet
+}
+
+func TestTCPPacketReservedBitsAnomaly(t *testing.T) {
+	require.Empty(t, parsePacket(t, synSegment).Anomalies(), "valid packet should not have anomalies")
+
+	data := slices.Clone(synSegment)
+	data[12] |= 0x0A
+
+	packet, err := tcp.ParsePacketWithOptions(data, netpacket.ParseOptions{Policy: netpacket.ParsePolicyStrict})
+	require.NoError(t, err, "reserved bits should not be rejected")
+	require.Equal(t, uint8(0x05), packet.GetHeader().Reserved)
+
+	anomalies := packet.Anomalies()
+	require.Len(t, anomalies, 1, "should report one anomaly")
+	require.Equal(t, netpacket.AnomalyReservedBits, anomalies[0].Code)
+	require.Equal(t, tcp.Kind, anomalies[0].Layer)
+	require.Equal(t, 12, anomalies[0].Offset)
+	require.Equal(t, "TCP reserved bits at offset 12: TCP reserved bits set to 101", anomalies[0].String())
+	require.Contains(t, packet.String(), "\tAnomalies:\n\t\tTCP reserved bits at offset 12")
+
+	packet, err = tcp.ParsePacketWithOptions(data, netpacket.ParseOptions{Policy: netpacket.ParsePolicyLenient})
+	require.NoError(t, err, "reserved bits should not be rejected with lenient policy")
+	require.True(t, packet.Anomalies().Has(netpacket.AnomalyReservedBits), "should report reserved bits")
+}
@@ -0,0 +1,160 @@
+// Copyright 2026
+// license that can be found in the LICENSE file.
+
+package tcp
+
+import (
+	"net"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/name212/netpacket"
+	"github.com/name212/netpacket/checksum"
+	"github.com/name212/netpacket/net/ip/v4"
+	"github.com/name212/netpacket/transport/tcp"
+)
+
+func TestTCPPacketRoundTrip(t *testing.T) {
+	for _, data := range [][]byte{synSegment, httpSegment} {
+		packet := parsePacket(t, data)
+
+		headerData, err := packet.GetHeader().MarshalBinary()
+		require.NoError(t, err, "should marshal header")
+		require.Equal(t, packet.GetHeaderData(), headerData, "marshaled header should be equal to original")
+
+		res, err := packet.MarshalBinary()
+		require.NoError(t, err, "should marshal")
+		require.Equal(t, data, res, "marshaled packet should be equal to original")
+	}
+}
+
+func TestEncodeTCPOptions(t *testing.T) {
+	t.Run("SYN options", func(t *testing.T) {
+		encoded, err := tcp.EncodeOptions([]tcp.Option{
+			tcp.NewMSSOption(1460),
+			tcp.NewSACKPermittedOption(),
+			tcp.NewTimestampsOption(tcp.Timestamps{Value: 2604421423}),
+			tcp.NewNOPOption(),
+			tcp.NewWindowScaleOption(7),
+		})
+		require.NoError(t, err, "should encode")
+		require.Equal(t, synSegment[20:40], encoded, "should be equal to original options")
+	})
+
+	t.Run("NOP padding", func(t *testing.T) {
+		sack, err := tcp.NewSACKOption(tcp.SACKBlock{LeftEdge: 1000, RightEdge: 2000})
+		require.NoError(t, err, "should create SACK option")
+
+		encoded, err := tcp.EncodeOptions([]tcp.Option{tcp.NewMSSOption(536), sack})
+		require.NoError(t, err, "should encode")
+		require.Equal(t, []byte{
+			0x02, 0x04, 0x02, 0x18,
+			0x05, 0x0a, 0x00, 0x00, 0x03, 0xe8, 0x00, 0x00, 0x07, 0xd0,
+			0x01, 0x01,
+		}, encoded, "options should be padded with NOP")
+
+		header := &tcp.Header{Options: encoded}
+		parsed, err := header.ParseOptions()
+		require.NoError(t, err, "should parse encoded options")
+		require.Len(t, parsed, 4, "should parse 4 options")
+	})
+
+	t.Run("too long", func(t *testing.T) {
+		ts := tcp.NewTimestampsOption(tcp.Timestamps{})
+		_, err := tcp.EncodeOptions([]tcp.Option{ts, ts, ts, ts, ts})
+		require.ErrorIs(t, err, netpacket.ErrCannotSerialize, "should not encode")
+		require.Contains(t, err.Error(), "TCP options length 52 exceeds 40")
+	})
+
+	t.Run("invalid SACK", func(t *testing.T) {
+		_, err := tcp.NewSACKOption()
+		require.Error(t, err, "should not create SACK without blocks")
+
+		_, err = tcp.NewOption(tcp.OptionNoOperation, []byte{0x01})
+		require.Error(t, err, "should not create NOP with data")
+	})
+}
+
+func TestSerializeTCPPacket(t *testing.T) {
+	header := &tcp.Header{
+		SourcePort:      42910,
+		DestinationPort: 80,
+		SequenceNumber:  5073867,
+		Flags:           tcp.FlagSYN,
+		Window:          64240,
+	}
+
+	err := header.SetOptions([]tcp.Option{
+		tcp.NewMSSOption(1460),
+		tcp.NewSACKPermittedOption(),
+		tcp.NewTimestampsOption(tcp.Timestamps{Value: 2604421423}),
+		tcp.NewNOPOption(),
+		tcp.NewWindowScaleOption(7),
+	})
+	require.NoError(t, err, "should set options")
+	require.Equal(t, 40, header.HeaderLen(), "header len should be set")
+
+	packet := tcp.NewPacket(header, nil)
+
+	_, err = packet.Serialize(netpacket.SerializeOptions{ComputeChecksums: true})
+	require.ErrorIs(t, err, netpacket.ErrCannotSerialize, "should not compute checksum without pseudo-header")
+
+	res, err := packet.Serialize(netpacket.SerializeOptions{
+		FixLengths:       true,
+		ComputeChecksums: true,
+		PseudoHeader:     synPseudoHeader(),
+	})
+	require.NoError(t, err, "should serialize")
+	require.Equal(t, synSegment, res, "serialized packet should be equal to original")
+
+	parsed := parsePacket(t, res)
+	require.Equal(t, "valid", parsed.VerifyChecksum(net.IPv4(10, 233, 233, 1), net.IPv4(216, 58, 206, 46)).String())
+
+	prefix := []byte{0x01, 0x02}
+
+	appended, err := packet.AppendTo(slices.Clone(prefix), netpacket.SerializeOptions{})
+	require.NoError(t, err, "should append")
+	require.Equal(t, append(slices.Clone(prefix), synSegment...), appended, "packet should be appended after prefix")
+}
+
+func TestSerializeIPv4WithTCPLayers(t *testing.T) {
+	pseudoHeader := synPseudoHeader()
+
+	ipHeader := &v4.Header{
+		Version:       4,
+		TTL:           64,
+		Protocol:      uint8(v4.ProtocolTCP),
+		SourceIP:      pseudoHeader.Source,
+		DestinationIP: pseudoHeader.Destination,
+	}
+
+	tcpHeader := parsePacket(t, synSegment).GetHeader()
+	tcpHeader.Checksum = 0
+
+	b := netpacket.NewSerializeBuffer()
+
+	// pseudo-header is passed to TCP layer from IPv4 layer
+	err := netpacket.SerializeLayers(b, netpacket.SerializeOptions{FixLengths: true, ComputeChecksums: true},
+		ipHeader,
+		tcpHeader,
+	)
+	require.NoError(t, err, "should serialize layers")
+
+	packet, err := v4.ParsePacketWithOptions(b.Bytes(), netpacket.ParseOptions{VerifyChecksum: true})
+	require.NoError(t, err, "should parse serialized packet")
+	require.Equal(t, synSegment, packet.GetPayload(), "TCP packet should be equal to original")
+
+	_, status, err := packet.VerifyTransportChecksum()
+	require.NoError(t, err, "should verify transport checksum")
+	require.Equal(t, checksum.StatusValid, status, "TCP checksum should be valid")
+}
+
+func synPseudoHeader() *checksum.IPv4PseudoHeader {
+	return &checksum.IPv4PseudoHeader{
+		Source:      net.IPv4(10, 233, 233, 1),
+		Destination: net.IPv4(216, 58, 206, 46),
+		Protocol:    uint8(v4.ProtocolTCP),
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2026
+// license that can be found in the LICENSE file.
+
+package udp
+
+import (
+	"net"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/name212/netpacket"
+	"github.com/name212/netpacket/tests"
+	"github.com/name212/netpacket/transport/udp"
+)
+
+func TestUDPParseWithCopy(t *testing.T) {
+	data := slices.Clone(dnsRequestDatagram)
+
+	datagram, err := udp.ParseDatagramWithOptions(data, netpacket.ParseOptions{Copy: true})
+	require.NoError(t, err, "should parse datagram")
+
+	header, err := udp.ParseHeaderWithOptions(data, netpacket.ParseOptions{Copy: true})
+	require.NoError(t, err, "should parse header")
+
+	tests.OverwriteBuffer(data)
+
+	require.Equal(t, dnsRequestDatagram[8:], datagram.GetPayload(), "payload should not alias data")
+	require.Equal(t, dnsRequestDatagram[:8], datagram.GetHeaderData(), "header data should not alias data")
+	assertHeader(t, header, 39290, 53, 36, 48731)
+}
+
+func TestUDPClone(t *testing.T) {
+	data := slices.Clone(dnsRequestDatagram)
+
+	datagram, err := udp.ParseDatagram(data)
+	require.NoError(t, err, "should parse datagram")
+
+	clone := datagram.Clone()
+
+	tests.OverwriteBuffer(data)
+
+	require.Equal(t, dnsRequestDatagram[8:], clone.GetPayload(), "payload should be copied")
+	require.NotEqual(t, dnsRequestDatagram[8:], datagram.GetPayload(), "original datagram should alias data")
+	assertHeader(t, clone.GetHeader(), 39290, 53, 36, 48731)
+
+	clone.GetHeader().SourcePort = 1
+	require.Equal(t, 39290, datagram.GetSourcePort(), "header should be copied")
+
+	serialized, err := clone.Serialize(netpacket.SerializeOptions{
+		ComputeChecksums: true,
+		PseudoHeader:     udpPseudoHeader(net.IPv4(10, 0, 0, 1), net.IPv4(10, 0, 0, 2)),
+	})
+	require.NoError(t, err, "should serialize clone")
+	require.Len(t, serialized, len(dnsRequestDatagram))
+}
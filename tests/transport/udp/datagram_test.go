@@ -4,10 +4,15 @@
 package udp
 
 import (
+	"encoding/binary"
+	"net"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/name212/netpacket"
+	"github.com/name212/netpacket/checksum"
 	"github.com/name212/netpacket/tests"
 	"github.com/name212/netpacket/transport/udp"
 )
@@ -35,6 +40,7 @@ func TestParseUDPDatagramWithoutPayload(t *testing.T) {
 
 	assertHeader(t, datagram.GetHeader(), 55338, 53, 37, 48732)
 	require.Empty(t, datagram.GetPayload(), "should parse empty payload")
+	require.True(t, datagram.Anomalies().Has(netpacket.AnomalyLengthExceedsData), "should report length exceeds data")
 
 	payload, err := udp.ExtractPayload(data)
 	require.NoError(t, err, "should extract payload")
@@ -49,6 +55,9 @@ UDP Datagram:
 		Destination port: 53
 		Datagram size: 37
 		Checksum: 48732
+	Checksum status: not verified
+	Anomalies:
+		UDP length exceeds data at offset 4: UDP length 37 exceeds data length 8
 	Payload len: 0
 `
 	tests.AssertStringer(t, datagram, expectedString)
@@ -84,6 +93,7 @@ UDP Datagram:
 		Destination port: 53
 		Datagram size: 36
 		Checksum: 48731
+	Checksum status: not verified
 	Payload len: 28
 `
 	tests.AssertStringer(t, datagram, expectedString)
@@ -96,3 +106,111 @@ func parseDatagram(t *testing.T, data []byte) *udp.Datagram {
 
 	return datagram
 }
+
+func TestUDPDatagramChecksum(t *testing.T) {
+	data := []byte{
+		0x99, 0x7a, 0x00, 0x35, 0x00, 0x24, 0x51, 0xf5,
+		0x42, 0x22, 0x01, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x06, 0x67, 0x6f, 0x6f,
+		0x67, 0x6c, 0x65, 0x03, 0x63, 0x6f, 0x6d, 0x00, 0x00, 0x01, 0x00, 0x01,
+	}
+
+	source := net.IPv4(172, 17, 0, 3)
+	destination := net.IPv4(9, 9, 9, 9)
+
+	datagram := parseDatagram(t, data)
+	require.Equal(t, checksum.StatusNotVerified, datagram.ChecksumStatus(), "checksum should not be verified after parse")
+	require.Equal(t, uint16(0x51f5), datagram.ComputeChecksum(source, destination), "checksum should be computed")
+	require.Equal(t, checksum.StatusValid, datagram.VerifyChecksum(source, destination), "checksum should be valid")
+	require.Equal(t, checksum.StatusValid, datagram.ChecksumStatus(), "checksum status should be saved")
+
+	require.Equal(t, checksum.StatusInvalid, datagram.VerifyChecksum(source, net.IPv4(8, 8, 8, 8)), "checksum should be invalid for other destination")
+
+	// AssertStringer Trim \n from expected
+	// use \n this for better observability (show in code as string present)
+	expectedString := `
+UDP Datagram:
+	Header:
+		Source port: 39290
+		Destination port: 53
+		Datagram size: 36
+		Checksum: 20981
+	Checksum status: invalid
+	Payload len: 28
+`
+	tests.AssertStringer(t, datagram, expectedString)
+}
+
+func TestUDPDatagramLengthAnomalies(t *testing.T) {
+	type testCase struct {
+		name   string
+		length uint16
+		extra  int
+		code   netpacket.AnomalyCode
+		offset int
+		// strictErr
+		// expected error with strict policy. Nil if datagram is not rejected
+		strictErr *netpacket.ParseError
+	}
+
+	testCases := []testCase{
+		{
+			name:   "length too small",
+			length: 4,
+			code:   netpacket.AnomalyInvalidLength,
+			offset: 4,
+			strictErr: &netpacket.ParseError{
+				Kind:     udp.Kind,
+				Field:    "length",
+				Offset:   4,
+				Expected: ">= 8",
+				Actual:   4,
+			},
+		},
+		{
+			name:   "length exceeds data",
+			length: 40,
+			code:   netpacket.AnomalyLengthExceedsData,
+			offset: 4,
+		},
+		{
+			name:   "trailing data",
+			length: 36,
+			extra:  6,
+			code:   netpacket.AnomalyTrailingData,
+			offset: 36,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data := append(slices.Clone(dnsRequestDatagram), make([]byte, tc.extra)...)
+			binary.BigEndian.PutUint16(data[4:6], tc.length)
+
+			strict, err := udp.ParseDatagram(data)
+			if tc.strictErr != nil {
+				tests.AssertParseError(t, err, netpacket.ErrCannotParseHeader, *tc.strictErr)
+			} else {
+				require.NoError(t, err, "should not reject with strict policy")
+				require.Len(t, strict.Anomalies(), 1, "should report one anomaly with strict policy")
+			}
+
+			datagram, err := udp.ParseDatagramWithOptions(data, netpacket.ParseOptions{Policy: netpacket.ParsePolicyLenient})
+			require.NoError(t, err, "should parse with lenient policy")
+
+			anomalies := datagram.Anomalies()
+			require.Len(t, anomalies, 1, "should report one anomaly")
+			require.Equal(t, tc.code, anomalies[0].Code)
+			require.Equal(t, udp.Kind, anomalies[0].Layer)
+			require.Equal(t, tc.offset, anomalies[0].Offset)
+			require.Len(t, datagram.Clone().Anomalies(), 1, "clone should keep anomalies")
+		})
+	}
+
+	require.Empty(t, parseDatagram(t, dnsRequestDatagram).Anomalies(), "valid datagram should not have anomalies")
+
+	t.Run("allow truncated", func(t *testing.T) {
+		datagram, err := udp.ParseDatagramWithOptions(dnsRequestDatagram[:20], netpacket.ParseOptions{AllowTruncated: true})
+		require.NoError(t, err, "should not reject truncated datagram")
+		require.True(t, datagram.Anomalies().Has(netpacket.AnomalyLengthExceedsData), "should report length exceeds data")
+	})
+}
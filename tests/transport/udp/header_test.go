@@ -6,6 +6,7 @@ package udp
 import (
 	"testing"
 
+	"github.com/name212/netpacket"
 	"github.com/name212/netpacket/transport/udp"
 	"github.com/stretchr/testify/require"
 
@@ -21,6 +22,18 @@ func TestParseUDPHeaderShortData(t *testing.T) {
 
 	require.Error(t, err, "should not parse")
 	require.Nil(t, header)
+
+	tests.AssertParseError(t, err, netpacket.ErrShortData, netpacket.ParseError{
+		Kind:     udp.Kind,
+		Field:    "data length",
+		Offset:   0,
+		Expected: ">= 8",
+		Actual:   2,
+	})
+
+	_, err = udp.ParseDatagram(datagram)
+	require.ErrorIs(t, err, netpacket.ErrCannotParseHeader, "datagram parser should wrap parse error")
+	require.ErrorIs(t, err, netpacket.ErrShortData, "datagram parser should keep short data cause")
 }
 
 func TestParseUDPHeader(t *testing.T) {
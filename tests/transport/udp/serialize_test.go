@@ -0,0 +1,174 @@
+// Copyright 2026
+// license that can be found in the LICENSE file.
+
+package udp
+
+import (
+	"net"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/name212/netpacket"
+	"github.com/name212/netpacket/checksum"
+	"github.com/name212/netpacket/net/ip/v4"
+	"github.com/name212/netpacket/transport/udp"
+)
+
+var dnsRequestPayload = []byte{
+	0x42, 0x22, 0x01, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x06, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x03, 0x63, 0x6f, 0x6d, 0x00, 0x00, 0x01, 0x00, 0x01,
+}
+
+var dnsRequestDatagram = slices.Concat([]byte{0x99, 0x7a, 0x00, 0x35, 0x00, 0x24, 0xbe, 0x5b}, dnsRequestPayload)
+
+func TestUDPHeaderMarshalBinary(t *testing.T) {
+	data := []byte{
+		0xd8, 0x2a, 0x00, 0x35, 0x00, 0x25, 0xbe, 0x5c,
+	}
+
+	header, err := udp.ParseHeader(data)
+	require.NoError(t, err, "should parse")
+
+	res, err := header.MarshalBinary()
+	require.NoError(t, err, "should marshal")
+	require.Equal(t, data, res, "marshaled header should be equal to original")
+}
+
+func TestUDPDatagramRoundTrip(t *testing.T) {
+	data := slices.Clone(dnsRequestDatagram)
+
+	datagram := parseDatagram(t, data)
+
+	res, err := datagram.MarshalBinary()
+	require.NoError(t, err, "should marshal")
+	require.Equal(t, data, res, "marshaled datagram should be equal to original")
+}
+
+func TestNewUDPDatagram(t *testing.T) {
+	datagram, err := udp.NewDatagram(39290, 53, dnsRequestPayload)
+	require.NoError(t, err, "should create datagram")
+
+	assertHeader(t, datagram.GetHeader(), 39290, 53, 36, 0)
+
+	_, err = datagram.Serialize(netpacket.SerializeOptions{ComputeChecksums: true})
+	require.ErrorIs(t, err, netpacket.ErrCannotSerialize, "should not compute checksum without pseudo-header")
+
+	res, err := datagram.Serialize(netpacket.SerializeOptions{
+		FixLengths:       true,
+		ComputeChecksums: true,
+		PseudoHeader:     udpPseudoHeader(net.IPv4(172, 17, 0, 3), net.IPv4(9, 9, 9, 9)),
+	})
+	require.NoError(t, err, "should serialize")
+
+	assertHeader(t, datagram.GetHeader(), 39290, 53, 36, 0x51f5)
+	require.Equal(t, append([]byte{0x99, 0x7a, 0x00, 0x35, 0x00, 0x24, 0x51, 0xf5}, dnsRequestPayload...), res)
+
+	_, err = udp.NewDatagram(39290, 53, make([]byte, 65535-7))
+	require.ErrorIs(t, err, netpacket.ErrCannotSerialize, "should not create datagram with length exceeds max")
+}
+
+func TestUDPDatagramAppendTo(t *testing.T) {
+	datagram, err := udp.NewDatagram(39290, 53, dnsRequestPayload)
+	require.NoError(t, err, "should create datagram")
+
+	prefix := []byte{0x01, 0x02}
+
+	res, err := datagram.AppendTo(slices.Clone(prefix), netpacket.SerializeOptions{
+		ComputeChecksums: true,
+		PseudoHeader:     udpPseudoHeader(net.IPv4(172, 17, 0, 3), net.IPv4(9, 9, 9, 9)),
+	})
+	require.NoError(t, err, "should append datagram")
+
+	expected := append(slices.Clone(prefix), 0x99, 0x7a, 0x00, 0x35, 0x00, 0x24, 0x51, 0xf5)
+	expected = append(expected, dnsRequestPayload...)
+	require.Equal(t, expected, res, "datagram should be appended after prefix")
+}
+
+func TestSerializeIPv4WithUDP(t *testing.T) {
+	source := net.IPv4(172, 17, 0, 3)
+	destination := net.IPv4(9, 9, 9, 9)
+	opts := netpacket.SerializeOptions{FixLengths: true, ComputeChecksums: true}
+
+	datagram, err := udp.NewDatagram(39290, 53, dnsRequestPayload)
+	require.NoError(t, err, "should create datagram")
+
+	datagramOpts := opts
+	datagramOpts.PseudoHeader = udpPseudoHeader(source, destination)
+
+	datagramData, err := datagram.Serialize(datagramOpts)
+	require.NoError(t, err, "should serialize datagram")
+
+	packet := v4.NewPacket(&v4.Header{
+		Version:        4,
+		Identification: 0x56af,
+		Flags:          0x02,
+		TTL:            64,
+		Protocol:       uint8(v4.ProtocolUDP),
+		SourceIP:       source,
+		DestinationIP:  destination,
+	}, datagramData)
+
+	res, err := packet.Serialize(opts)
+	require.NoError(t, err, "should serialize packet")
+
+	expected := append([]byte{
+		0x45, 0x00, 0x00, 0x38, 0x56, 0xaf, 0x40, 0x00, 0x40, 0x11, 0x25, 0xe0, 0xac, 0x11,
+		0x00, 0x03, 0x09, 0x09, 0x09, 0x09, 0x99, 0x7a, 0x00, 0x35, 0x00, 0x24, 0x51, 0xf5,
+	}, dnsRequestPayload...)
+	require.Equal(t, expected, res, "serialized packet should be equal to expected")
+
+	parsed, err := v4.ParsePacketWithOptions(res, netpacket.ParseOptions{VerifyChecksum: true})
+	require.NoError(t, err, "should parse serialized packet")
+
+	_, status, err := parsed.VerifyTransportChecksum()
+	require.NoError(t, err, "should verify transport checksum")
+	require.Equal(t, "valid", status.String(), "transport checksum should be valid")
+}
+
+func TestSerializeIPv4WithUDPLayers(t *testing.T) {
+	source := net.IPv4(172, 17, 0, 3)
+	destination := net.IPv4(9, 9, 9, 9)
+
+	ipHeader := &v4.Header{
+		Version:        4,
+		Identification: 0x56af,
+		Flags:          0x02,
+		TTL:            64,
+		Protocol:       uint8(v4.ProtocolUDP),
+		SourceIP:       source,
+		DestinationIP:  destination,
+	}
+
+	// pseudo-header is passed to UDP layer from IPv4 layer
+	udpHeader := &udp.Header{
+		SourcePort:      39290,
+		DestinationPort: 53,
+	}
+
+	b := netpacket.NewSerializeBufferExpectedSize(28, len(dnsRequestPayload))
+
+	err := netpacket.SerializeLayers(b, netpacket.SerializeOptions{FixLengths: true, ComputeChecksums: true},
+		ipHeader,
+		udpHeader,
+		netpacket.Payload(dnsRequestPayload),
+	)
+	require.NoError(t, err, "should serialize layers")
+
+	expected := append([]byte{
+		0x45, 0x00, 0x00, 0x38, 0x56, 0xaf, 0x40, 0x00, 0x40, 0x11, 0x25, 0xe0, 0xac, 0x11,
+		0x00, 0x03, 0x09, 0x09, 0x09, 0x09, 0x99, 0x7a, 0x00, 0x35, 0x00, 0x24, 0x51, 0xf5,
+	}, dnsRequestPayload...)
+	require.Equal(t, expected, b.Bytes(), "serialized layers should be equal to expected")
+	require.Equal(t, uint16(36), udpHeader.Length, "UDP length should be fixed")
+	require.Equal(t, 56, ipHeader.GetTotalLen(), "IPv4 total length should be fixed")
+}
+
+func udpPseudoHeader(source, destination net.IP) *checksum.IPv4PseudoHeader {
+	return &checksum.IPv4PseudoHeader{
+		Source:      source,
+		Destination: destination,
+		Protocol:    uint8(v4.ProtocolUDP),
+	}
+}
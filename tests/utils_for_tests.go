@@ -4,12 +4,15 @@
 package tests
 
 import (
+	"bytes"
 	"encoding/base64"
 	"fmt"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/name212/netpacket"
 )
 
 func AssertDataAsBase64(t *testing.T, expected string, data []byte, length int) {
@@ -21,6 +24,28 @@ func AssertStringer(t *testing.T, s fmt.Stringer, expected string) {
 	require.Equal(t, trimLn(expected), s.String(), "String should returns correct string without left and right ln")
 }
 
+// AssertParseError
+// asserts that err is netpacket.ParseError which matches cause with errors.Is
+func AssertParseError(t *testing.T, err error, cause error, expected netpacket.ParseError) {
+	t.Helper()
+
+	require.ErrorIs(t, err, cause, "should match %v", cause)
+
+	var parseErr *netpacket.ParseError
+	require.ErrorAs(t, err, &parseErr, "should be parse error")
+	require.Equal(t, expected.Kind, parseErr.Kind, "parse error kind")
+	require.Equal(t, expected.Field, parseErr.Field, "parse error field")
+	require.Equal(t, expected.Offset, parseErr.Offset, "parse error offset")
+	require.Equal(t, expected.Expected, parseErr.Expected, "parse error expected value")
+	require.Equal(t, expected.Actual, parseErr.Actual, "parse error actual value")
+}
+
 func trimLn(s string) string {
 	return strings.Trim(s, "\n")
 }
+
+// OverwriteBuffer
+// fills data with 0xFF to check that parsed or cloned values do not alias data
+func OverwriteBuffer(data []byte) {
+	copy(data, bytes.Repeat([]byte{0xFF}, len(data)))
+}
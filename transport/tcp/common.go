@@ -3,6 +3,63 @@
 
 package tcp
 
-import "github.com/name212/netpacket"
+import (
+	"fmt"
 
-const Kind netpacket.Kind = "TCP"
+	"github.com/name212/netpacket"
+)
+
+const (
+	minHeaderLength  = 20
+	maxHeaderLength  = 60
+	maxOptionsLength = maxHeaderLength - minHeaderLength
+	// protocolNumber
+	// TCP protocol number in IPv4 header
+	protocolNumber = 6
+
+	Kind netpacket.Kind = "TCP"
+)
+
+func isValidSegment(data []byte) error {
+	if len(data) < minHeaderLength {
+		return netpacket.NewShortDataErr(Kind, "data length", 0, fmt.Sprintf(">= %d", minHeaderLength), len(data))
+	}
+
+	return nil
+}
+
+func validateHeaderLen(headerLengthBytes int, dataLen int) error {
+	if headerLengthBytes < minHeaderLength {
+		return netpacket.NewInvalidFieldErr(Kind, "header length", 12, fmt.Sprintf(">= %d", minHeaderLength), headerLengthBytes)
+	}
+
+	if headerLengthBytes > dataLen {
+		return netpacket.NewShortDataErr(Kind, "header length", 12, fmt.Sprintf("<= data length %d", dataLen), headerLengthBytes)
+	}
+
+	return nil
+}
+
+func validateOptionsLen(optionsLen int) error {
+	if optionsLen%4 != 0 {
+		return netpacket.WrapCannotSerializeErr(
+			fmt.Errorf("TCP options length %d is not multiple of 4", optionsLen),
+		)
+	}
+
+	if optionsLen > maxOptionsLength {
+		return netpacket.WrapCannotSerializeErr(
+			fmt.Errorf("TCP options length %d exceeds %d", optionsLen, maxOptionsLength),
+		)
+	}
+
+	return nil
+}
+
+func headerLen(words uint8) int {
+	return int(words) * 4
+}
+
+func extractDataOffset(data []byte) uint8 {
+	return data[12] >> 4
+}
@@ -3,28 +3,355 @@
 
 package tcp
 
-import "github.com/name212/netpacket"
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
 
-type Header struct{}
+	"github.com/name212/netpacket"
+	"github.com/name212/netpacket/checksum"
+	stringsutils "github.com/name212/netpacket/utils/strings"
+)
 
-// ParseHeader
+type Flags uint16
+
+// AE (former NS)	Accurate ECN
+// CWR	Congestion Window Reduced
+// ECE	ECN-Echo
+// URG	Urgent pointer field is significant
+// ACK	Acknowledgment field is significant
+// PSH	Push function
+// RST	Reset the connection
+// SYN	Synchronize sequence numbers
+// FIN	Last packet from sender
+const (
+	FlagFIN Flags = 1 << iota
+	FlagSYN
+	FlagRST
+	FlagPSH
+	FlagACK
+	FlagURG
+	FlagECE
+	FlagCWR
+	FlagAE
+)
+
+var flagsNames = []struct {
+	flag Flags
+	name string
+}{
+	{flag: FlagAE, name: "AE"},
+	{flag: FlagCWR, name: "CWR"},
+	{flag: FlagECE, name: "ECE"},
+	{flag: FlagURG, name: "URG"},
+	{flag: FlagACK, name: "ACK"},
+	{flag: FlagPSH, name: "PSH"},
+	{flag: FlagRST, name: "RST"},
+	{flag: FlagSYN, name: "SYN"},
+	{flag: FlagFIN, name: "FIN"},
+}
+
+// Has
+// returns true if all bits from flag are set
+func (f Flags) Has(flag Flags) bool {
+	return f&flag == flag
+}
+
+func (f Flags) String() string {
+	names := make([]string, 0, len(flagsNames))
+
+	for _, n := range flagsNames {
+		if f.Has(n.flag) {
+			names = append(names, n.name)
+		}
+	}
+
+	if len(names) == 0 {
+		return "none"
+	}
+
+	return strings.Join(names, " ")
+}
+
+// Header represents the structure of a TCP header
+type Header struct {
+	SourcePort      uint16
+	DestinationPort uint16
+	SequenceNumber  uint32
+	AckNumber       uint32
+	// DataOffset
+	// header length in 32-bit words
+	DataOffset uint8
+	// Reserved
+	// 3 reserved bits between data offset and AE flag. Should be 0
+	Reserved      uint8
+	Flags         Flags
+	Window        uint16
+	Checksum      uint16
+	UrgentPointer uint16
+	Options       []byte
+}
+
+// ParseHeader parses the TCP header from the given byte slice
 // ParseHeader save slices from data. You should copy data before parse
 // to avoid hold full original data in memory
-// Warning! TODO not implemented
 func ParseHeader(data []byte) (*Header, error) {
-	return &Header{}, nil
+	if err := isValidSegment(data); err != nil {
+		return nil, err
+	}
+
+	header := &Header{
+		SourcePort:      binary.BigEndian.Uint16(data[0:2]),
+		DestinationPort: binary.BigEndian.Uint16(data[2:4]),
+		SequenceNumber:  binary.BigEndian.Uint32(data[4:8]),
+		AckNumber:       binary.BigEndian.Uint32(data[8:12]),
+		DataOffset:      extractDataOffset(data),
+		Reserved:        (data[12] >> 1) & 0x07,
+		Flags:           Flags(binary.BigEndian.Uint16(data[12:14]) & 0x01FF),
+		Window:          binary.BigEndian.Uint16(data[14:16]),
+		Checksum:        binary.BigEndian.Uint16(data[16:18]),
+		UrgentPointer:   binary.BigEndian.Uint16(data[18:20]),
+	}
+
+	headerLengthBytes := header.HeaderLen()
+
+	if err := validateHeaderLen(headerLengthBytes, len(data)); err != nil {
+		return nil, err
+	}
+
+	if headerLengthBytes > minHeaderLength {
+		header.Options = data[minHeaderLength:headerLengthBytes]
+	}
+
+	return header, nil
+}
+
+// ParseHeaderWithOptions
+// same as ParseHeader but applies additional parsing options
+// With opts.Copy returned header does not alias data
+// opts.VerifyChecksum is ignored because pseudo-header is required
+func ParseHeaderWithOptions(data []byte, opts netpacket.ParseOptions) (*Header, error) {
+	header, err := ParseHeader(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Copy {
+		return header.Clone(), nil
+	}
+
+	return header, nil
+}
+
+// Clone
+// returns deep copy of header which does not alias parsed data
+func (h *Header) Clone() *Header {
+	res := *h
+	res.Options = slices.Clone(h.Options)
+
+	return &res
+}
+
+func (h *Header) GetSourcePort() int {
+	return int(h.SourcePort)
+}
+
+func (h *Header) GetDestinationPort() int {
+	return int(h.DestinationPort)
+}
+
+func (h *Header) GetFlags() Flags {
+	return h.Flags
 }
 
 func (h *Header) HeaderLen() int {
-	// todo need implementation
-	return 0
+	return headerLen(h.DataOffset)
 }
 
 func (h *Header) Kind() netpacket.Kind {
 	return Kind
 }
 
+// SetOptions
+// encodes options to Options with NOP padding and sets DataOffset
+func (h *Header) SetOptions(opts []Option) error {
+	encoded, err := EncodeOptions(opts)
+	if err != nil {
+		return err
+	}
+
+	h.Options = encoded
+	h.DataOffset = uint8((minHeaderLength + len(encoded)) / 4)
+
+	return nil
+}
+
+// FixLengths
+// sets DataOffset from options length
+func (h *Header) FixLengths() error {
+	if err := validateOptionsLen(len(h.Options)); err != nil {
+		return err
+	}
+
+	h.DataOffset = uint8((minHeaderLength + len(h.Options)) / 4)
+
+	return nil
+}
+
+// AppendTo
+// appends header bytes to buf as is without fixing data offset and checksum
+// DataOffset should correspond to options length
+func (h *Header) AppendTo(buf []byte) ([]byte, error) {
+	if err := h.validateForSerialize(); err != nil {
+		return nil, err
+	}
+
+	start := len(buf)
+	buf = slices.Grow(buf, h.HeaderLen())
+	buf = buf[:start+minHeaderLength]
+	h.putFixedPart(buf[start:])
+
+	return append(buf, h.Options...), nil
+}
+
+// MarshalBinary
+// returns header bytes. See AppendTo
+func (h *Header) MarshalBinary() ([]byte, error) {
+	return h.AppendTo(make([]byte, 0, minHeaderLength+len(h.Options)))
+}
+
+// SerializeTo
+// prepends header to buffer. Current buffer bytes are treated as payload
+// With opts.FixLengths sets DataOffset
+// With opts.ComputeChecksums sets Checksum. opts.PseudoHeader is required for it,
+// netpacket.SerializeLayers sets it from enclosing IPv4 header
+// Header fields are changed in place
+func (h *Header) SerializeTo(b *netpacket.SerializeBuffer, opts netpacket.SerializeOptions) error {
+	if err := h.prepareForSerialize(b.Bytes(), opts); err != nil {
+		return err
+	}
+
+	bytes, err := b.PrependBytes(h.HeaderLen())
+	if err != nil {
+		return err
+	}
+
+	h.putFixedPart(bytes)
+	copy(bytes[minHeaderLength:], h.Options)
+
+	return nil
+}
+
+// prepareForSerialize
+// fixes data offset and computes checksum for payload with opts
+func (h *Header) prepareForSerialize(payload []byte, opts netpacket.SerializeOptions) error {
+	if opts.FixLengths {
+		if err := h.FixLengths(); err != nil {
+			return err
+		}
+	}
+
+	if opts.ComputeChecksums {
+		if opts.PseudoHeader == nil {
+			return netpacket.WrapCannotSerializeErr(
+				errors.New("TCP pseudo-header is not set for checksum calculation"),
+			)
+		}
+
+		h.Checksum = h.computeChecksum(opts.PseudoHeader.Source, opts.PseudoHeader.Destination, payload)
+	}
+
+	return h.validateForSerialize()
+}
+
+func (h *Header) validateForSerialize() error {
+	if err := validateOptionsLen(len(h.Options)); err != nil {
+		return err
+	}
+
+	if h.HeaderLen() != minHeaderLength+len(h.Options) {
+		return netpacket.WrapCannotSerializeErr(
+			fmt.Errorf("TCP header length %d does not match options length %d", h.HeaderLen(), len(h.Options)),
+		)
+	}
+
+	return nil
+}
+
+func (h *Header) computeChecksum(source, destination net.IP, payload []byte) uint16 {
+	var fixedPart [minHeaderLength]byte
+
+	h.putFixedPart(fixedPart[:])
+	// checksum field
+	fixedPart[16] = 0
+	fixedPart[17] = 0
+
+	pseudoHeader := checksum.IPv4PseudoHeader{
+		Source:      source,
+		Destination: destination,
+		Protocol:    protocolNumber,
+	}
+
+	return pseudoHeader.Checksum(fixedPart[:], h.Options, payload)
+}
+
+func (h *Header) putFixedPart(b []byte) {
+	binary.BigEndian.PutUint16(b[0:2], h.SourcePort)
+	binary.BigEndian.PutUint16(b[2:4], h.DestinationPort)
+	binary.BigEndian.PutUint32(b[4:8], h.SequenceNumber)
+	binary.BigEndian.PutUint32(b[8:12], h.AckNumber)
+	binary.BigEndian.PutUint16(b[12:14], uint16(h.DataOffset&0x0F)<<12|uint16(h.Reserved&0x07)<<9|uint16(h.Flags&0x01FF))
+	binary.BigEndian.PutUint16(b[14:16], h.Window)
+	binary.BigEndian.PutUint16(b[16:18], h.Checksum)
+	binary.BigEndian.PutUint16(b[18:20], h.UrgentPointer)
+}
+
 func (h *Header) String() string {
-	// todo need implementation
-	return "TCP header: Not implemented yet"
+	s := strings.Builder{}
+
+	s.WriteString(stringsutils.FmtLn("Source port: %d", h.SourcePort))
+	s.WriteString(stringsutils.FmtLn("Destination port: %d", h.DestinationPort))
+	s.WriteString(stringsutils.FmtLn("Sequence number: %d", h.SequenceNumber))
+	s.WriteString(stringsutils.FmtLn("Acknowledgment number: %d", h.AckNumber))
+	s.WriteString(stringsutils.FmtLn("Header Size: %d", h.HeaderLen()))
+	s.WriteString(stringsutils.FmtLn("Reserved: %d", h.Reserved))
+	s.WriteString(stringsutils.FmtLn("Flags: %s", h.Flags.String()))
+	s.WriteString(stringsutils.FmtLn("Window: %d", h.Window))
+	s.WriteString(stringsutils.FmtLn("Urgent pointer: %d", h.UrgentPointer))
+	h.writeOptions(&s)
+	s.WriteString(fmt.Sprintf("Checksum: %d", h.Checksum))
+
+	return s.String()
+}
+
+func (h *Header) ParseOptions() ([]Option, error) {
+	return parseOptions(h.Options)
+}
+
+func (h *Header) writeOptions(s *strings.Builder) {
+	if h.Options == nil {
+		s.WriteString(stringsutils.FmtLn("No options set"))
+		return
+	}
+
+	opts, err := h.ParseOptions()
+	if err != nil {
+		s.WriteString(stringsutils.FmtLn("Cannot parse options: %v", err))
+		return
+	}
+
+	s.WriteString(stringsutils.FmtLn("Options:"))
+
+	optsStringsSlice := make([]string, 0, len(opts))
+
+	for _, opt := range opts {
+		optsStringsSlice = append(optsStringsSlice, opt.String())
+	}
+
+	optsStr := strings.Join(optsStringsSlice, "\n")
+
+	s.WriteString(stringsutils.ShiftOnTabs(stringsutils.FmtLn(optsStr), 1))
 }
@@ -0,0 +1,623 @@
+// Copyright 2026
+// license that can be found in the LICENSE file.
+
+package tcp
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/name212/netpacket"
+	stringsutils "github.com/name212/netpacket/utils/strings"
+)
+
+var ErrWrongOptionKind = errors.New("wrong option kind")
+
+type OptionKind uint8
+
+// 0	EOL	End of Option List
+// 1	NOP	No-Operation
+// 2	MSS	Maximum Segment Size
+// 3	WS	Window Scale
+// 4	SACK-P	SACK Permitted
+// 5	SACK	Selective Acknowledgment
+// 8	TS	Timestamps
+// 19	MD5	MD5 Signature Option (obsoleted by TCP-AO)
+// 28	UTO	User Timeout Option
+// 29	TCP-AO	TCP Authentication Option
+// 30	MPTCP	Multipath TCP
+// 34	TFO	TCP Fast Open Cookie
+const (
+	OptionEndOfList      OptionKind = 0
+	OptionNoOperation    OptionKind = 1
+	OptionMSS            OptionKind = 2
+	OptionWindowScale    OptionKind = 3
+	OptionSACKPermitted  OptionKind = 4
+	OptionSACK           OptionKind = 5
+	OptionTimestamps     OptionKind = 8
+	OptionMD5Signature   OptionKind = 19
+	OptionUserTimeout    OptionKind = 28
+	OptionAuthentication OptionKind = 29
+	OptionMPTCP          OptionKind = 30
+	OptionFastOpen       OptionKind = 34
+)
+
+type MPTCPSubtype uint8
+
+const (
+	MPTCPCapable        MPTCPSubtype = 0
+	MPTCPJoin           MPTCPSubtype = 1
+	MPTCPDataSequence   MPTCPSubtype = 2
+	MPTCPAddAddress     MPTCPSubtype = 3
+	MPTCPRemoveAddress  MPTCPSubtype = 4
+	MPTCPPriority       MPTCPSubtype = 5
+	MPTCPFail           MPTCPSubtype = 6
+	MPTCPFastClose      MPTCPSubtype = 7
+	MPTCPTCPRST         MPTCPSubtype = 8
+	MPTCPPrivateUseLast MPTCPSubtype = 0xF
+)
+
+var mptcpSubtypesMap = map[MPTCPSubtype]string{
+	MPTCPCapable:        "MP_CAPABLE",
+	MPTCPJoin:           "MP_JOIN",
+	MPTCPDataSequence:   "DSS",
+	MPTCPAddAddress:     "ADD_ADDR",
+	MPTCPRemoveAddress:  "REMOVE_ADDR",
+	MPTCPPriority:       "MP_PRIO",
+	MPTCPFail:           "MP_FAIL",
+	MPTCPFastClose:      "MP_FASTCLOSE",
+	MPTCPTCPRST:         "MP_TCPRST",
+	MPTCPPrivateUseLast: "MP_EXPERIMENTAL",
+}
+
+func (s MPTCPSubtype) String() string {
+	name, ok := mptcpSubtypesMap[s]
+	if !ok {
+		name = "UNKNOWN"
+	}
+
+	return fmt.Sprintf("%s(%d)", name, s)
+}
+
+type SACKBlock struct {
+	LeftEdge  uint32
+	RightEdge uint32
+}
+
+type Timestamps struct {
+	// Value
+	// TSval field
+	Value uint32
+	// EchoReply
+	// TSecr field
+	EchoReply uint32
+}
+
+type AuthenticationOption struct {
+	KeyID      uint8
+	RNextKeyID uint8
+	MAC        []byte
+}
+
+const (
+	mssOptionLength           = 4
+	windowScaleOptionLength   = 3
+	sackPermittedOptionLength = 2
+	sackBlockLength           = 8
+	maxSACKBlocks             = 4
+	timestampsOptionLength    = 10
+	md5SignatureOptionLength  = 18
+	userTimeoutOptionLength   = 4
+	minAuthOptionLength       = 4
+	minMPTCPOptionLength      = 3
+	minFastOpenCookieLength   = 4
+	maxFastOpenCookieLength   = 16
+)
+
+type Option struct {
+	kind   uint8
+	length uint8
+	data   []byte
+}
+
+func NewEndOfListOption() Option {
+	return Option{kind: uint8(OptionEndOfList), length: 1}
+}
+
+func NewNOPOption() Option {
+	return Option{kind: uint8(OptionNoOperation), length: 1}
+}
+
+func NewMSSOption(mss uint16) Option {
+	return newOption(OptionMSS, binary.BigEndian.AppendUint16(nil, mss))
+}
+
+func NewWindowScaleOption(shift uint8) Option {
+	return newOption(OptionWindowScale, []byte{shift})
+}
+
+func NewSACKPermittedOption() Option {
+	return newOption(OptionSACKPermitted, nil)
+}
+
+// NewSACKOption
+// creates SACK option from 1 to 4 blocks
+func NewSACKOption(blocks ...SACKBlock) (Option, error) {
+	data := make([]byte, 0, len(blocks)*sackBlockLength)
+
+	for _, block := range blocks {
+		data = binary.BigEndian.AppendUint32(data, block.LeftEdge)
+		data = binary.BigEndian.AppendUint32(data, block.RightEdge)
+	}
+
+	return NewOption(OptionSACK, data)
+}
+
+func NewTimestampsOption(ts Timestamps) Option {
+	data := make([]byte, 0, timestampsOptionLength-2)
+	data = binary.BigEndian.AppendUint32(data, ts.Value)
+	data = binary.BigEndian.AppendUint32(data, ts.EchoReply)
+
+	return newOption(OptionTimestamps, data)
+}
+
+// NewOption
+// creates option with kind and data. Length is calculated from data
+// Data length is validated for known kinds
+func NewOption(kind OptionKind, data []byte) (Option, error) {
+	if kind == OptionEndOfList || kind == OptionNoOperation {
+		if len(data) > 0 {
+			opt := Option{kind: uint8(kind)}
+			return Option{}, opt.wrapError("should not contain data")
+		}
+
+		return Option{kind: uint8(kind), length: 1}, nil
+	}
+
+	if len(data)+2 > maxOptionsLength {
+		opt := Option{kind: uint8(kind)}
+		return Option{}, opt.wrapError("data length %d too long", len(data))
+	}
+
+	opt := newOption(kind, data)
+	if expected, ok := opt.checkLength(); !ok {
+		return Option{}, opt.wrapError("invalid length %d. Must be %s", opt.GetLength(), expected)
+	}
+
+	return opt, nil
+}
+
+// EncodeOptions
+// encodes options to header bytes and adds NOP padding to 32-bit alignment
+func EncodeOptions(opts []Option) ([]byte, error) {
+	res := make([]byte, 0, maxOptionsLength)
+
+	for i := range opts {
+		res = opts[i].AppendTo(res)
+	}
+
+	for len(res)%4 != 0 {
+		res = append(res, uint8(OptionNoOperation))
+	}
+
+	if err := validateOptionsLen(len(res)); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func newOption(kind OptionKind, data []byte) Option {
+	return Option{
+		kind:   uint8(kind),
+		length: uint8(len(data) + 2),
+		data:   data,
+	}
+}
+
+func parseOptions(data []byte) ([]Option, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	res := make([]Option, 0, 4)
+	offset := minHeaderLength
+
+	for len(data) > 0 {
+		opt := Option{kind: data[0]}
+
+		switch opt.GetKind() {
+		case OptionEndOfList:
+			return res, nil
+		case OptionNoOperation:
+			opt.length = 1
+			data = data[1:]
+			offset++
+			res = append(res, opt)
+		default:
+			if len(data) < 2 {
+				return nil, netpacket.NewShortDataErr(Kind, opt.fieldName("data length"), offset, ">= 2", len(data))
+			}
+			opt.length = data[1]
+			intLen := opt.GetLength()
+			if len(data) < intLen {
+				return nil, netpacket.NewShortDataErr(
+					Kind,
+					opt.fieldName("length"),
+					offset+1,
+					fmt.Sprintf("<= remaining header length %d", len(data)),
+					intLen,
+				)
+			}
+			if intLen < 2 {
+				return nil, netpacket.NewInvalidFieldErr(Kind, opt.fieldName("length"), offset+1, ">= 2", intLen)
+			}
+			opt.data = data[2:intLen]
+			if expected, ok := opt.checkLength(); !ok {
+				return nil, netpacket.NewInvalidFieldErr(Kind, opt.fieldName("length"), offset+1, expected, intLen)
+			}
+			data = data[intLen:]
+			offset += intLen
+			res = append(res, opt)
+		}
+	}
+
+	return res, nil
+}
+
+// checkLength
+// validates option length for known kinds
+// returns expected length description and false if length is invalid
+func (o *Option) checkLength() (string, bool) {
+	l := o.GetLength()
+
+	assertLength := func(expected int) (string, bool) {
+		return strconv.Itoa(expected), l == expected
+	}
+
+	assertMinLength := func(minLen int) (string, bool) {
+		return fmt.Sprintf(">= %d", minLen), l >= minLen
+	}
+
+	switch o.GetKind() {
+	case OptionMSS:
+		return assertLength(mssOptionLength)
+	case OptionWindowScale:
+		return assertLength(windowScaleOptionLength)
+	case OptionSACKPermitted:
+		return assertLength(sackPermittedOptionLength)
+	case OptionSACK:
+		blocksLen := len(o.data)
+		if blocksLen == 0 || blocksLen%sackBlockLength != 0 || blocksLen/sackBlockLength > maxSACKBlocks {
+			return fmt.Sprintf("2 + %d*n where n from 1 to %d", sackBlockLength, maxSACKBlocks), false
+		}
+	case OptionTimestamps:
+		return assertLength(timestampsOptionLength)
+	case OptionMD5Signature:
+		return assertLength(md5SignatureOptionLength)
+	case OptionUserTimeout:
+		return assertLength(userTimeoutOptionLength)
+	case OptionAuthentication:
+		return assertMinLength(minAuthOptionLength)
+	case OptionMPTCP:
+		return assertMinLength(minMPTCPOptionLength)
+	case OptionFastOpen:
+		cookieLen := len(o.data)
+		if cookieLen == 0 {
+			// cookie request
+			return "", true
+		}
+		if cookieLen < minFastOpenCookieLength || cookieLen > maxFastOpenCookieLength || cookieLen%2 != 0 {
+			return fmt.Sprintf(
+				"2 or 2 + even cookie length from %d to %d",
+				minFastOpenCookieLength,
+				maxFastOpenCookieLength,
+			), false
+		}
+	}
+
+	return "", true
+}
+
+func (o *Option) GetLength() int {
+	return int(o.length)
+}
+
+func (o *Option) GetData() []byte {
+	return o.data
+}
+
+func (o *Option) GetKind() OptionKind {
+	return OptionKind(o.kind)
+}
+
+func (o *Option) KindShort() string {
+	return getOptionDescription(o.GetKind()).short
+}
+
+func (o *Option) KindShortWithID() string {
+	return fmt.Sprintf("%s(%d)", o.KindShort(), o.GetKind())
+}
+
+func (o *Option) KindLong() string {
+	return getOptionDescription(o.GetKind()).long
+}
+
+// AppendTo
+// appends option bytes to buf
+func (o *Option) AppendTo(buf []byte) []byte {
+	switch o.GetKind() {
+	case OptionEndOfList, OptionNoOperation:
+		return append(buf, o.kind)
+	default:
+		buf = append(buf, o.kind, o.length)
+		return append(buf, o.data...)
+	}
+}
+
+// MSS
+// returns maximum segment size value from MSS option
+func (o *Option) MSS() (uint16, error) {
+	if err := o.assertKind(OptionMSS); err != nil {
+		return 0, err
+	}
+
+	return binary.BigEndian.Uint16(o.data), nil
+}
+
+// WindowScale
+// returns shift count from window scale option
+func (o *Option) WindowScale() (uint8, error) {
+	if err := o.assertKind(OptionWindowScale); err != nil {
+		return 0, err
+	}
+
+	return o.data[0], nil
+}
+
+func (o *Option) IsSACKPermitted() bool {
+	return o.GetKind() == OptionSACKPermitted
+}
+
+// SACKBlocks
+// returns left and right edges of all blocks from SACK option
+func (o *Option) SACKBlocks() ([]SACKBlock, error) {
+	if err := o.assertKind(OptionSACK); err != nil {
+		return nil, err
+	}
+
+	blocks := make([]SACKBlock, 0, len(o.data)/sackBlockLength)
+
+	for d := o.data; len(d) >= sackBlockLength; d = d[sackBlockLength:] {
+		blocks = append(blocks, SACKBlock{
+			LeftEdge:  binary.BigEndian.Uint32(d[0:4]),
+			RightEdge: binary.BigEndian.Uint32(d[4:8]),
+		})
+	}
+
+	return blocks, nil
+}
+
+// Timestamps
+// returns TSval and TSecr from timestamps option
+func (o *Option) Timestamps() (*Timestamps, error) {
+	if err := o.assertKind(OptionTimestamps); err != nil {
+		return nil, err
+	}
+
+	return &Timestamps{
+		Value:     binary.BigEndian.Uint32(o.data[0:4]),
+		EchoReply: binary.BigEndian.Uint32(o.data[4:8]),
+	}, nil
+}
+
+// FastOpenCookie
+// returns TCP Fast Open cookie. Empty cookie means cookie request
+// Returns subslice of header options data
+func (o *Option) FastOpenCookie() ([]byte, error) {
+	if err := o.assertKind(OptionFastOpen); err != nil {
+		return nil, err
+	}
+
+	return o.data, nil
+}
+
+// MPTCPSubtype
+// returns Multipath TCP option subtype (first 4 bits of option data)
+func (o *Option) MPTCPSubtype() (MPTCPSubtype, error) {
+	if err := o.assertKind(OptionMPTCP); err != nil {
+		return 0, err
+	}
+
+	return MPTCPSubtype(o.data[0] >> 4), nil
+}
+
+// MD5Signature
+// returns 16 bytes digest from TCP MD5 signature option
+// Returns subslice of header options data
+func (o *Option) MD5Signature() ([]byte, error) {
+	if err := o.assertKind(OptionMD5Signature); err != nil {
+		return nil, err
+	}
+
+	return o.data, nil
+}
+
+// Authentication
+// returns key ids and MAC from TCP-AO option
+// MAC is subslice of header options data
+func (o *Option) Authentication() (*AuthenticationOption, error) {
+	if err := o.assertKind(OptionAuthentication); err != nil {
+		return nil, err
+	}
+
+	return &AuthenticationOption{
+		KeyID:      o.data[0],
+		RNextKeyID: o.data[1],
+		MAC:        o.data[2:],
+	}, nil
+}
+
+func (o *Option) writeData(b *strings.Builder) {
+	switch o.GetKind() {
+	case OptionMSS:
+		mss, _ := o.MSS()
+		b.WriteString(stringsutils.FmtWithTabPrefix("MSS: %d", mss))
+		return
+	case OptionWindowScale:
+		shift, _ := o.WindowScale()
+		b.WriteString(stringsutils.FmtWithTabPrefix("Shift count: %d", shift))
+		return
+	case OptionSACK:
+		blocks, _ := o.SACKBlocks()
+		blocksStrings := make([]string, 0, len(blocks))
+		for _, block := range blocks {
+			blocksStrings = append(blocksStrings, fmt.Sprintf("%d-%d", block.LeftEdge, block.RightEdge))
+		}
+		b.WriteString(stringsutils.FmtLnWithTabPrefix("Blocks:"))
+		b.WriteString(stringsutils.ShiftOnTabs(strings.Join(blocksStrings, "\n"), 2))
+		return
+	case OptionTimestamps:
+		ts, _ := o.Timestamps()
+		b.WriteString(stringsutils.FmtLnWithTabPrefix("TSval: %d", ts.Value))
+		b.WriteString(stringsutils.FmtWithTabPrefix("TSecr: %d", ts.EchoReply))
+		return
+	case OptionFastOpen:
+		if len(o.data) == 0 {
+			b.WriteString(stringsutils.FmtWithTabPrefix("Cookie request"))
+			return
+		}
+		b.WriteString(stringsutils.FmtLnWithTabPrefix("Cookie:"))
+		b.WriteString(stringsutils.ShiftOnTabs(stringsutils.BytesToHexWithWrap(o.data, 8), 2))
+		return
+	case OptionMPTCP:
+		subtype, _ := o.MPTCPSubtype()
+		b.WriteString(stringsutils.FmtLnWithTabPrefix("Subtype: %s", subtype.String()))
+	case OptionAuthentication:
+		ao, _ := o.Authentication()
+		b.WriteString(stringsutils.FmtLnWithTabPrefix("Key ID: %d", ao.KeyID))
+		b.WriteString(stringsutils.FmtLnWithTabPrefix("RNext key ID: %d", ao.RNextKeyID))
+		if len(ao.MAC) == 0 {
+			b.WriteString(stringsutils.FmtWithTabPrefix("No MAC"))
+			return
+		}
+		b.WriteString(stringsutils.FmtLnWithTabPrefix("MAC:"))
+		b.WriteString(stringsutils.ShiftOnTabs(stringsutils.BytesToHexWithWrap(ao.MAC, 8), 2))
+		return
+	}
+
+	data := o.GetData()
+	if len(data) == 0 {
+		b.WriteString("\tNo data")
+		return
+	}
+
+	b.WriteString(stringsutils.FmtLnWithTabPrefix("Hex data:"))
+	b.WriteString(stringsutils.ShiftOnTabs(stringsutils.BytesToHexWithWrap(data, 8), 2))
+}
+
+func (o *Option) String() string {
+	b := strings.Builder{}
+
+	b.WriteString(stringsutils.FmtLn("Option:"))
+	b.WriteString(stringsutils.FmtLnWithTabPrefix("Kind: %s", o.KindShortWithID()))
+	b.WriteString(stringsutils.FmtLnWithTabPrefix("Kind description: %s", o.KindLong()))
+	b.WriteString(stringsutils.FmtLnWithTabPrefix("Full Length: %d", o.GetLength()))
+	o.writeData(&b)
+
+	return b.String()
+}
+
+func (o *Option) assertKind(kind OptionKind) error {
+	if o.GetKind() != kind {
+		return fmt.Errorf("%w: %s is not %s", ErrWrongOptionKind, o.KindShortWithID(), getOptionDescription(kind).short)
+	}
+
+	return nil
+}
+
+// fieldName
+// returns name of option field for ParseError
+func (o *Option) fieldName(field string) string {
+	return fmt.Sprintf("option %s %s", o.KindShortWithID(), field)
+}
+
+func (o *Option) wrapError(f string, args ...any) error {
+	f = fmt.Sprintf("option %s: ", o.KindShortWithID()) + f
+	return fmt.Errorf(f, args...)
+}
+
+type optionDescription struct {
+	short string
+	long  string
+}
+
+var optionKindsMap = map[OptionKind]*optionDescription{
+	OptionEndOfList: {
+		short: "EOL",
+		long:  "End Of Option List",
+	},
+	OptionNoOperation: {
+		short: "NOP",
+		long:  "No Operation",
+	},
+	OptionMSS: {
+		short: "MSS",
+		long:  "Maximum Segment Size",
+	},
+	OptionWindowScale: {
+		short: "WS",
+		long:  "Window Scale",
+	},
+	OptionSACKPermitted: {
+		short: "SACK-P",
+		long:  "SACK Permitted",
+	},
+	OptionSACK: {
+		short: "SACK",
+		long:  "Selective Acknowledgment",
+	},
+	OptionTimestamps: {
+		short: "TS",
+		long:  "Timestamps",
+	},
+	OptionMD5Signature: {
+		short: "MD5",
+		long:  "MD5 Signature",
+	},
+	OptionUserTimeout: {
+		short: "UTO",
+		long:  "User Timeout",
+	},
+	OptionAuthentication: {
+		short: "TCP-AO",
+		long:  "TCP Authentication Option",
+	},
+	OptionMPTCP: {
+		short: "MPTCP",
+		long:  "Multipath TCP",
+	},
+	OptionFastOpen: {
+		short: "TFO",
+		long:  "TCP Fast Open Cookie",
+	},
+}
+
+func unknownOptionDescription(kind OptionKind) *optionDescription {
+	return &optionDescription{
+		short: "UNKNOWN",
+		long:  fmt.Sprintf("Unknown: %d", kind),
+	}
+}
+
+func getOptionDescription(kind OptionKind) *optionDescription {
+	description, ok := optionKindsMap[kind]
+	if ok {
+		return description
+	}
+
+	return unknownOptionDescription(kind)
+}
@@ -4,9 +4,14 @@
 package tcp
 
 import (
-	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
 
 	"github.com/name212/netpacket"
+	"github.com/name212/netpacket/checksum"
+	stringsutils "github.com/name212/netpacket/utils/strings"
 )
 
 type Packet struct {
@@ -14,21 +19,75 @@ type Packet struct {
 
 	headerData []byte
 	payload    []byte
+
+	checksumStatus checksum.Status
+
+	anomalies netpacket.Anomalies
+}
+
+// NewPacket
+// creates packet from header and payload for serialization
+// Header data is empty for created packet
+func NewPacket(header *Header, payload []byte) *Packet {
+	return &Packet{
+		header:  header,
+		payload: payload,
+	}
 }
 
 // ParsePacket
-// ParsePacked save s
-// Warning! TODO not implemented
+// Parse header and extract payload from segment
+// Also save header data as subslice data
+// ParsePacket save slices from data. You should copy data before parse
+// to avoid hold full data in memory
 func ParsePacket(data []byte) (*Packet, error) {
+	return ParsePacketWithOptions(data, netpacket.ParseOptions{})
+}
+
+// ParsePacketWithOptions
+// same as ParsePacket but applies additional parsing options
+// With opts.Copy data is copied before parsing, so packet does not alias data
+// opts.VerifyChecksum is ignored, use VerifyChecksum with addresses of enclosing IPv4 packet
+// Reserved bits are not rejected with any opts.Policy and reported by Anomalies
+func ParsePacketWithOptions(data []byte, opts netpacket.ParseOptions) (*Packet, error) {
+	data = opts.PrepareData(data)
+
 	header, err := ParseHeader(data)
 	if err != nil {
-		return nil, err
+		return nil, netpacket.WrapParseErr(err)
+	}
+
+	headerLengthBytes := header.HeaderLen()
+
+	packet := &Packet{
+		header:     header,
+		headerData: data[:headerLengthBytes],
+		payload:    extractPayload(data, headerLengthBytes),
 	}
-	// todo need implement
+
+	if header.Reserved != 0 {
+		// RFC 9293 receivers must ignore reserved bits, so they are not rejected with any policy
+		packet.anomalies.Add(netpacket.Anomaly{
+			Code:    netpacket.AnomalyReservedBits,
+			Layer:   Kind,
+			Offset:  12,
+			Message: fmt.Sprintf("TCP reserved bits set to %03b", header.Reserved),
+		})
+	}
+
+	return packet, nil
+}
+
+// Clone
+// returns deep copy of packet which does not alias parsed data
+func (p *Packet) Clone() *Packet {
 	return &Packet{
-		header:  header,
-		payload: nil,
-	}, nil
+		header:         p.header.Clone(),
+		headerData:     slices.Clone(p.headerData),
+		payload:        slices.Clone(p.payload),
+		checksumStatus: p.checksumStatus,
+		anomalies:      slices.Clone(p.anomalies),
+	}
 }
 
 func (p *Packet) GetPayload() []byte {
@@ -43,29 +102,160 @@ func (p *Packet) GetHeaderData() []byte {
 	return p.headerData
 }
 
+// Anomalies
+// returns protocol violations found during parsing
+// Empty for valid packets and for packets which were not parsed
+func (p *Packet) Anomalies() netpacket.Anomalies {
+	return p.anomalies
+}
+
 func (p *Packet) Kind() netpacket.Kind {
 	return Kind
 }
 
+// Contents
+// returns header data. See GetHeaderData
+func (p *Packet) Contents() []byte {
+	return p.headerData
+}
+
+// Payload
+// returns payload. See GetPayload
+func (p *Packet) Payload() []byte {
+	return p.payload
+}
+
+// NextLayerKind
+// returns netpacket.KindPayload if packet carries payload
+// because application protocols are not decoded
+func (p *Packet) NextLayerKind() netpacket.Kind {
+	if len(p.payload) == 0 {
+		return ""
+	}
+
+	return netpacket.KindPayload
+}
+
 func (p *Packet) GetSourcePort() int {
-	// todo implement
-	return 0
+	return p.header.GetSourcePort()
 }
 
 func (p *Packet) GetDestinationPort() int {
-	// todo implement
-	return 0
+	return p.header.GetDestinationPort()
+}
+
+// ComputeChecksum
+// calculates checksum over IPv4 pseudo-header, header fields, options and payload
+// Checksum field is treated as zero during calculation
+func (p *Packet) ComputeChecksum(source, destination net.IP) uint16 {
+	return p.header.computeChecksum(source, destination, p.payload)
+}
+
+// VerifyChecksum
+// verifies checksum with IPv4 pseudo-header and saves result for ChecksumStatus
+func (p *Packet) VerifyChecksum(source, destination net.IP) checksum.Status {
+	if p.ComputeChecksum(source, destination) == p.header.Checksum {
+		p.checksumStatus = checksum.StatusValid
+	} else {
+		p.checksumStatus = checksum.StatusInvalid
+	}
+
+	return p.checksumStatus
+}
+
+// ChecksumStatus
+// returns result of last VerifyChecksum call
+// returns checksum.StatusNotVerified if checksum was not verified
+func (p *Packet) ChecksumStatus() checksum.Status {
+	return p.checksumStatus
+}
+
+// SerializeTo
+// prepends payload and header to buffer. See Header.SerializeTo
+func (p *Packet) SerializeTo(b *netpacket.SerializeBuffer, opts netpacket.SerializeOptions) error {
+	if err := netpacket.Payload(p.GetPayload()).SerializeTo(b, opts); err != nil {
+		return err
+	}
+
+	return p.GetHeader().SerializeTo(b, opts)
+}
+
+// AppendTo
+// appends header and payload bytes to buf. See Header.SerializeTo
+func (p *Packet) AppendTo(buf []byte, opts netpacket.SerializeOptions) ([]byte, error) {
+	payload := p.GetPayload()
+	header := p.GetHeader()
+
+	if err := header.prepareForSerialize(payload, opts); err != nil {
+		return nil, err
+	}
+
+	buf = slices.Grow(buf, header.HeaderLen()+len(payload))
+
+	buf, err := header.AppendTo(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(buf, payload...), nil
+}
+
+// Serialize
+// returns packet bytes. See Header.SerializeTo
+func (p *Packet) Serialize(opts netpacket.SerializeOptions) ([]byte, error) {
+	b := netpacket.NewSerializeBufferExpectedSize(maxHeaderLength, len(p.GetPayload()))
+
+	if err := p.SerializeTo(b, opts); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// MarshalBinary
+// returns packet bytes as is without fixing data offset and checksum
+func (p *Packet) MarshalBinary() ([]byte, error) {
+	return p.Serialize(netpacket.SerializeOptions{})
 }
 
 func (p *Packet) String() string {
-	// todo implement
-	return "TCP Packet:\n\tNot implemented yet"
+	b := strings.Builder{}
+
+	b.WriteString(stringsutils.FmtLn("TCP Packet:"))
+	b.WriteString(stringsutils.FmtLnWithTabPrefix("Header:"))
+	b.WriteString(stringsutils.ShiftOnTabs(stringsutils.FmtLn(p.GetHeader().String()), 2))
+	b.WriteString(stringsutils.FmtLnWithTabPrefix("Checksum status: %s", p.ChecksumStatus()))
+	if anomalies := p.Anomalies(); len(anomalies) > 0 {
+		b.WriteString(stringsutils.FmtLnWithTabPrefix("Anomalies:"))
+		b.WriteString(stringsutils.ShiftOnTabs(stringsutils.FmtLn(anomalies.String()), 2))
+	}
+	b.WriteString(stringsutils.FmtWithTabPrefix("Payload len: %d", len(p.GetPayload())))
+
+	return b.String()
 }
 
 // ExtractPayload extract payload from data without full parsing header
 // ExtractPayload returns subslice from data. You should copy data before parse
 // to avoid hold full data in memory
-// Warning! TODO not implemented
 func ExtractPayload(data []byte) ([]byte, error) {
-	return nil, netpacket.WrapNotImplementedErr(errors.New("TCP"))
+	if err := isValidSegment(data); err != nil {
+		return nil, err
+	}
+
+	headerLengthBytes := headerLen(extractDataOffset(data))
+
+	if err := validateHeaderLen(headerLengthBytes, len(data)); err != nil {
+		return nil, err
+	}
+
+	return extractPayload(data, headerLengthBytes), nil
+}
+
+func extractPayload(data []byte, headerLengthBytes int) []byte {
+	var payload []byte
+	if len(data) > headerLengthBytes {
+		payload = data[headerLengthBytes:]
+	}
+
+	return payload
 }
@@ -4,20 +4,24 @@
 package udp
 
 import (
-	"errors"
+	"fmt"
 
 	"github.com/name212/netpacket"
 )
 
 const (
-	headerLength = 8
+	headerLength      = 8
+	maxDatagramLength = 0xFFFF
+	// protocolNumber
+	// UDP protocol number in IPv4 header
+	protocolNumber = 17
 
 	Kind netpacket.Kind = "UDP"
 )
 
 func isValidDatagram(data []byte) error {
 	if len(data) < headerLength {
-		return netpacket.WrapShortDataErr(errors.New("UDP datagram"))
+		return netpacket.NewShortDataErr(Kind, "data length", 0, fmt.Sprintf(">= %d", headerLength), len(data))
 	}
 
 	return nil
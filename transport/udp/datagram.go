@@ -4,9 +4,13 @@
 package udp
 
 import (
+	"fmt"
+	"net"
+	"slices"
 	"strings"
 
 	"github.com/name212/netpacket"
+	"github.com/name212/netpacket/checksum"
 	stringsutils "github.com/name212/netpacket/utils/strings"
 )
 
@@ -15,6 +19,31 @@ type Datagram struct {
 
 	headerData []byte
 	payload    []byte
+
+	checksumStatus checksum.Status
+
+	anomalies netpacket.Anomalies
+}
+
+// NewDatagram
+// creates datagram from ports and payload for serialization
+// Length in header is filled from payload length
+// Returns ErrCannotSerialize error if datagram length exceeds max UDP length
+// Header data is empty for created datagram
+func NewDatagram(sourcePort, destinationPort uint16, payload []byte) (*Datagram, error) {
+	datagramLen, err := datagramLength(len(payload))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Datagram{
+		header: &Header{
+			SourcePort:      sourcePort,
+			DestinationPort: destinationPort,
+			Length:          datagramLen,
+		},
+		payload: payload,
+	}, nil
 }
 
 // ParseDatagram
@@ -23,16 +52,47 @@ type Datagram struct {
 // ParseDatagram save slices from data. You should copy data before parse
 // to avoid hold full data in memory
 func ParseDatagram(data []byte) (*Datagram, error) {
+	return ParseDatagramWithOptions(data, netpacket.ParseOptions{})
+}
+
+// ParseDatagramWithOptions
+// same as ParseDatagram but applies additional parsing options
+// With opts.Copy data is copied before parsing, so datagram does not alias data
+// opts.VerifyChecksum is ignored, use VerifyChecksum with addresses of enclosing IPv4 packet
+// Length field smaller than header length is rejected with strict opts.Policy
+// and reported by Anomalies with lenient one. Length field greater than data length
+// (for example in first fragment) and data beyond length field are reported by Anomalies always
+func ParseDatagramWithOptions(data []byte, opts netpacket.ParseOptions) (*Datagram, error) {
+	data = opts.PrepareData(data)
+
 	header, err := ParseHeader(data)
 	if err != nil {
-		return nil, netpacket.WrapCannotParseHeaderErr(err)
+		return nil, netpacket.WrapParseErr(err)
 	}
 
-	return &Datagram{
+	datagram := &Datagram{
 		header:     header,
 		headerData: data[:headerLength],
 		payload:    extractPayload(data),
-	}, nil
+	}
+
+	if err := checkLength(header, len(data), opts, &datagram.anomalies); err != nil {
+		return nil, err
+	}
+
+	return datagram, nil
+}
+
+// Clone
+// returns deep copy of datagram which does not alias parsed data
+func (d *Datagram) Clone() *Datagram {
+	return &Datagram{
+		header:         d.header.Clone(),
+		headerData:     slices.Clone(d.headerData),
+		payload:        slices.Clone(d.payload),
+		checksumStatus: d.checksumStatus,
+		anomalies:      slices.Clone(d.anomalies),
+	}
 }
 
 func (d *Datagram) GetPayload() []byte {
@@ -47,10 +107,40 @@ func (d *Datagram) GetHeaderData() []byte {
 	return d.headerData
 }
 
+// Anomalies
+// returns protocol violations found during parsing
+// Empty for valid datagrams and for datagrams which were not parsed
+func (d *Datagram) Anomalies() netpacket.Anomalies {
+	return d.anomalies
+}
+
 func (d *Datagram) Kind() netpacket.Kind {
 	return Kind
 }
 
+// Contents
+// returns header data. See GetHeaderData
+func (d *Datagram) Contents() []byte {
+	return d.headerData
+}
+
+// Payload
+// returns payload. See GetPayload
+func (d *Datagram) Payload() []byte {
+	return d.payload
+}
+
+// NextLayerKind
+// returns netpacket.KindPayload if datagram carries payload
+// because application protocols are not decoded
+func (d *Datagram) NextLayerKind() netpacket.Kind {
+	if len(d.payload) == 0 {
+		return ""
+	}
+
+	return netpacket.KindPayload
+}
+
 func (d *Datagram) GetSourcePort() int {
 	return d.header.GetSourcePort()
 }
@@ -59,12 +149,98 @@ func (d *Datagram) GetDestinationPort() int {
 	return d.header.GetDestinationPort()
 }
 
+// ComputeChecksum
+// calculates checksum over IPv4 pseudo-header, header fields and payload
+// Checksum field is treated as zero during calculation.
+// Payload is limited by datagram length from header
+// Zero result returns as 0xFFFF because zero means "not computed" for UDP
+func (d *Datagram) ComputeChecksum(source, destination net.IP) uint16 {
+	return d.header.computeChecksum(source, destination, d.datagramPayload())
+}
+
+// VerifyChecksum
+// verifies checksum with IPv4 pseudo-header and saves result for ChecksumStatus
+// Zero checksum in header means that sender did not compute checksum,
+// checksum.StatusNotComputed returns in this case
+func (d *Datagram) VerifyChecksum(source, destination net.IP) checksum.Status {
+	switch {
+	case d.header.Checksum == 0:
+		d.checksumStatus = checksum.StatusNotComputed
+	case d.ComputeChecksum(source, destination) == d.header.Checksum:
+		d.checksumStatus = checksum.StatusValid
+	default:
+		d.checksumStatus = checksum.StatusInvalid
+	}
+
+	return d.checksumStatus
+}
+
+// ChecksumStatus
+// returns result of last VerifyChecksum call
+// returns checksum.StatusNotVerified if checksum was not verified
+func (d *Datagram) ChecksumStatus() checksum.Status {
+	return d.checksumStatus
+}
+
+// SerializeTo
+// prepends payload and header to buffer. See Header.SerializeTo
+func (d *Datagram) SerializeTo(b *netpacket.SerializeBuffer, opts netpacket.SerializeOptions) error {
+	if err := netpacket.Payload(d.GetPayload()).SerializeTo(b, opts); err != nil {
+		return err
+	}
+
+	return d.GetHeader().SerializeTo(b, opts)
+}
+
+// AppendTo
+// appends header and payload bytes to buf. See Header.SerializeTo
+func (d *Datagram) AppendTo(buf []byte, opts netpacket.SerializeOptions) ([]byte, error) {
+	payload := d.GetPayload()
+	header := d.GetHeader()
+
+	if err := header.prepareForSerialize(payload, opts); err != nil {
+		return nil, err
+	}
+
+	buf = slices.Grow(buf, headerLength+len(payload))
+
+	buf, err := header.AppendTo(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(buf, payload...), nil
+}
+
+// Serialize
+// returns datagram bytes. See Header.SerializeTo
+func (d *Datagram) Serialize(opts netpacket.SerializeOptions) ([]byte, error) {
+	b := netpacket.NewSerializeBufferExpectedSize(headerLength, len(d.GetPayload()))
+
+	if err := d.SerializeTo(b, opts); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// MarshalBinary
+// returns datagram bytes as is without fixing length and checksum
+func (d *Datagram) MarshalBinary() ([]byte, error) {
+	return d.Serialize(netpacket.SerializeOptions{})
+}
+
 func (d *Datagram) String() string {
 	b := strings.Builder{}
 
 	b.WriteString(stringsutils.FmtLn("UDP Datagram:"))
 	b.WriteString(stringsutils.FmtLnWithTabPrefix("Header:"))
 	b.WriteString(stringsutils.ShiftOnTabs(stringsutils.FmtLn(d.GetHeader().String()), 2))
+	b.WriteString(stringsutils.FmtLnWithTabPrefix("Checksum status: %s", d.ChecksumStatus()))
+	if anomalies := d.Anomalies(); len(anomalies) > 0 {
+		b.WriteString(stringsutils.FmtLnWithTabPrefix("Anomalies:"))
+		b.WriteString(stringsutils.ShiftOnTabs(stringsutils.FmtLn(anomalies.String()), 2))
+	}
 	b.WriteString(stringsutils.FmtWithTabPrefix("Payload len: %d", len(d.GetPayload())))
 
 	return b.String()
@@ -81,6 +257,15 @@ func ExtractPayload(data []byte) ([]byte, error) {
 	return extractPayload(data), nil
 }
 
+func (d *Datagram) datagramPayload() []byte {
+	payloadLen := d.header.DatagramLen() - headerLength
+	if payloadLen >= 0 && payloadLen < len(d.payload) {
+		return d.payload[:payloadLen]
+	}
+
+	return d.payload
+}
+
 func extractPayload(data []byte) []byte {
 	var payload []byte
 	if len(data) > headerLength {
@@ -89,3 +274,34 @@ func extractPayload(data []byte) []byte {
 
 	return payload
 }
+
+// checkLength
+// checks length field with length of parsed data
+// Length smaller than header length is handled with opts.Policy,
+// length greater than data length and trailing data are added to anomalies always
+func checkLength(header *Header, dataLen int, opts netpacket.ParseOptions, anomalies *netpacket.Anomalies) error {
+	datagramLen := header.DatagramLen()
+
+	switch {
+	case datagramLen < headerLength:
+		err := netpacket.NewInvalidFieldErr(Kind, "length", 4, fmt.Sprintf(">= %d", headerLength), datagramLen)
+		return opts.Violation(anomalies, netpacket.AnomalyInvalidLength, err)
+	case datagramLen > dataLen:
+		// datagram from first fragment or truncated capture is not rejected
+		anomalies.Add(netpacket.Anomaly{
+			Code:    netpacket.AnomalyLengthExceedsData,
+			Layer:   Kind,
+			Offset:  4,
+			Message: fmt.Sprintf("UDP length %d exceeds data length %d", datagramLen, dataLen),
+		})
+	case datagramLen < dataLen:
+		anomalies.Add(netpacket.Anomaly{
+			Code:    netpacket.AnomalyTrailingData,
+			Layer:   Kind,
+			Offset:  datagramLen,
+			Message: fmt.Sprintf("%d bytes beyond UDP length %d", dataLen-datagramLen, datagramLen),
+		})
+	}
+
+	return nil
+}
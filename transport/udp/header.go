@@ -5,10 +5,14 @@ package udp
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
+	"net"
+	"slices"
 	"strings"
 
 	"github.com/name212/netpacket"
+	"github.com/name212/netpacket/checksum"
 	stringsutils "github.com/name212/netpacket/utils/strings"
 )
 
@@ -35,6 +39,21 @@ func ParseHeader(data []byte) (*Header, error) {
 	}, nil
 }
 
+// ParseHeaderWithOptions
+// same as ParseHeader. Header never aliases data, so opts.Copy does not change result
+// opts.VerifyChecksum is ignored because pseudo-header is required
+func ParseHeaderWithOptions(data []byte, _ netpacket.ParseOptions) (*Header, error) {
+	return ParseHeader(data)
+}
+
+// Clone
+// returns deep copy of header
+func (h *Header) Clone() *Header {
+	res := *h
+
+	return &res
+}
+
 func (h *Header) GetSourcePort() int {
 	return int(h.SourcePort)
 }
@@ -55,6 +74,111 @@ func (h *Header) Kind() netpacket.Kind {
 	return Kind
 }
 
+// AppendTo
+// appends header bytes to buf as is without fixing length and checksum
+func (h *Header) AppendTo(buf []byte) ([]byte, error) {
+	start := len(buf)
+	buf = slices.Grow(buf, headerLength)
+	buf = buf[:start+headerLength]
+	h.putHeader(buf[start:])
+
+	return buf, nil
+}
+
+// MarshalBinary
+// returns header bytes. See AppendTo
+func (h *Header) MarshalBinary() ([]byte, error) {
+	return h.AppendTo(make([]byte, 0, headerLength))
+}
+
+// SerializeTo
+// prepends header to buffer. Current buffer bytes are treated as payload
+// With opts.FixLengths sets Length
+// With opts.ComputeChecksums sets Checksum. opts.PseudoHeader is required for it,
+// netpacket.SerializeLayers sets it from enclosing IPv4 header
+// Header fields are changed in place
+func (h *Header) SerializeTo(b *netpacket.SerializeBuffer, opts netpacket.SerializeOptions) error {
+	if err := h.prepareForSerialize(b.Bytes(), opts); err != nil {
+		return err
+	}
+
+	bytes, err := b.PrependBytes(headerLength)
+	if err != nil {
+		return err
+	}
+
+	h.putHeader(bytes)
+
+	return nil
+}
+
+// prepareForSerialize
+// fixes length and computes checksum for payload with opts
+func (h *Header) prepareForSerialize(payload []byte, opts netpacket.SerializeOptions) error {
+	if opts.FixLengths {
+		datagramLen, err := datagramLength(len(payload))
+		if err != nil {
+			return err
+		}
+
+		h.Length = datagramLen
+	}
+
+	if opts.ComputeChecksums {
+		if opts.PseudoHeader == nil {
+			return netpacket.WrapCannotSerializeErr(
+				errors.New("UDP pseudo-header is not set for checksum calculation"),
+			)
+		}
+
+		h.Checksum = h.computeChecksum(opts.PseudoHeader.Source, opts.PseudoHeader.Destination, payload)
+	}
+
+	return nil
+}
+
+func datagramLength(payloadLen int) (uint16, error) {
+	datagramLen := headerLength + payloadLen
+	if datagramLen > maxDatagramLength {
+		return 0, netpacket.WrapCannotSerializeErr(
+			fmt.Errorf("UDP datagram length %d exceeds %d", datagramLen, maxDatagramLength),
+		)
+	}
+
+	return uint16(datagramLen), nil
+}
+
+// computeChecksum
+// Zero result returns as 0xFFFF because zero means "not computed" for UDP
+func (h *Header) computeChecksum(source, destination net.IP, payload []byte) uint16 {
+	var header [headerLength]byte
+
+	h.putHeader(header[:])
+	// checksum field
+	header[6] = 0
+	header[7] = 0
+
+	pseudoHeader := checksum.IPv4PseudoHeader{
+		Source:      source,
+		Destination: destination,
+		Protocol:    protocolNumber,
+	}
+
+	res := pseudoHeader.Checksum(header[:], payload)
+	if res == 0 {
+		return 0xFFFF
+	}
+
+	return res
+}
+
+func (h *Header) putHeader(b []byte) {
+	binary.BigEndian.PutUint16(b[0:2], h.SourcePort)
+	binary.BigEndian.PutUint16(b[2:4], h.DestinationPort)
+	binary.BigEndian.PutUint16(b[4:6], h.Length)
+	binary.BigEndian.PutUint16(b[6:8], h.Checksum)
+}
+
 func (h *Header) String() string {
 	s := strings.Builder{}
 